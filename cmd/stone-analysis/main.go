@@ -1,13 +1,23 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"stone-analysis/internal/analyze"
+	"stone-analysis/internal/capacity"
 	"stone-analysis/internal/cypher"
 	"stone-analysis/internal/decypher"
+	"stone-analysis/internal/detect"
+	"stone-analysis/internal/dtmf"
+	"stone-analysis/internal/fec"
+	"stone-analysis/internal/fsk"
+	"stone-analysis/internal/payload"
+	"stone-analysis/internal/quality"
+	"stone-analysis/internal/robustness"
 	"stone-analysis/internal/utils"
+	"stone-analysis/internal/wav"
 	"strconv"
 )
 
@@ -15,11 +25,60 @@ func main() {
 	analyzeFlag := flag.Bool("analyze", false, "Run in analyze mode")
 	cypherFlag := flag.Bool("cypher", false, "Run in cypher mode")
 	decypherFlag := flag.Bool("decypher", false, "Run in decypher mode")
+	checkFlag := flag.Bool("check", false, "Check whether a file carries a hidden payload")
+	detectFlag := flag.Bool("detect", false, "Run LSB steganalysis on a file")
+	capacityFlag := flag.Bool("capacity", false, "Report how many bytes each method can hide")
+	fskEncodeFlag := flag.Bool("fsk-encode", false, "Encode a message as FSK tones in a WAV file")
+	fskDecodeFlag := flag.Bool("fsk-decode", false, "Demodulate an FSK transmission from a WAV file")
+	dtmfEncodeFlag := flag.Bool("dtmf-encode", false, "Synthesize DTMF digits into a WAV file")
+	dtmfDecodeFlag := flag.Bool("dtmf-decode", false, "Detect DTMF digits in a WAV file")
+	compareFlag := flag.Bool("compare", false, "Report quality metrics between two WAV files")
+	robustnessFlag := flag.Bool("robustness", false, "Measure how each method survives common attacks")
+	keyFlag := flag.String("key", "", "Passphrase used to encrypt or decrypt the payload")
+	passphraseFlag := flag.String("passphrase", "", "Alias for --key")
+	methodFlag := flag.String("method", "", "Embedding method (default lsb, decypher tries all)")
+	inputFlag := flag.String("input", "", "Hide the content of this file (- for stdin) instead of MESSAGE")
+	outputFlag := flag.String("output", "", "Write the recovered payload to this file (- for stdout)")
+	compressFlag := flag.Bool("compress", false, "Compress the payload before embedding")
+	qualityFlag := flag.Bool("quality", false, "Report quality metrics of the cypher output")
+	fecFlag := flag.String("fec", "none", "Error correction applied to the payload: none, hamming or rs")
+	strengthFlag := flag.Float64("strength", 0, "Embedding strength of the dsss method")
+	stegoKeyFlag := flag.String("stego-key", "", "Key that scatters the payload across the samples")
+	signKeyFlag := flag.String("sign-key", "", "Ed25519 private key (PEM) used to sign the payload")
+	verifyKeyFlag := flag.String("verify-key", "", "Ed25519 public key (PEM) used to verify the payload signature")
+	bandFlag := flag.String("band", "audible", "FSK band: audible or ultrasonic")
+	baudFlag := flag.Float64("baud", 0, "FSK symbol rate (default 100)")
+	viewFlag := flag.String("view", "downmix", "Channels analyzed: downmix, each or midside")
+	ratePolicyFlag := flag.String("rate-policy", "strict", "Sample rates accepted: strict (48 kHz) or permissive (8-384 kHz)")
+	channelFlag := flag.Int("channel", 0, "Channel that carries the payload (0 spreads it across all channels)")
 
 	flag.Parse()
 
 	args := flag.Args()
 
+	scheme, err := fec.ParseScheme(*fecFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		utils.DisplayHelp()
+		os.Exit(84)
+	}
+
+	ratePolicy, err := wav.ParsePolicy(*ratePolicyFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		utils.DisplayHelp()
+		os.Exit(84)
+	}
+
+	passphrase := *keyFlag
+	if *passphraseFlag != "" {
+		if passphrase != "" && passphrase != *passphraseFlag {
+			fmt.Fprintln(os.Stderr, "error: --key and --passphrase differ")
+			os.Exit(84)
+		}
+		passphrase = *passphraseFlag
+	}
+
 	modesSet := 0
 	if *analyzeFlag {
 		modesSet++
@@ -30,6 +89,33 @@ func main() {
 	if *decypherFlag {
 		modesSet++
 	}
+	if *checkFlag {
+		modesSet++
+	}
+	if *capacityFlag {
+		modesSet++
+	}
+	if *detectFlag {
+		modesSet++
+	}
+	if *fskEncodeFlag {
+		modesSet++
+	}
+	if *fskDecodeFlag {
+		modesSet++
+	}
+	if *dtmfEncodeFlag {
+		modesSet++
+	}
+	if *dtmfDecodeFlag {
+		modesSet++
+	}
+	if *robustnessFlag {
+		modesSet++
+	}
+	if *compareFlag {
+		modesSet++
+	}
 
 	if modesSet == 0 {
 		utils.DisplayHelp()
@@ -66,23 +152,58 @@ func main() {
 			utils.DisplayHelp()
 			os.Exit(84)
 		}
-		analyze.Analyze(inFile, n)
+		if err := analyze.Analyze(inFile, n, analyze.Options{View: *viewFlag, RatePolicy: ratePolicy}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
 	} else if *cypherFlag {
-		if len(args) != 3 {
+		expectedArgs := 3
+		if *inputFlag != "" {
+			expectedArgs = 2
+		}
+		if len(args) != expectedArgs {
 			utils.DisplayHelp()
 			os.Exit(84)
 		}
 
 		inFile := args[0]
 		outFile := args[1]
-		message := args[2]
 
 		if err := utils.CheckFileExists(inFile); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			utils.DisplayHelp()
 			os.Exit(84)
 		}
-		cypher.Cypher(inFile, outFile, message)
+
+		var message []byte
+		var metadata *payload.Metadata
+		if *inputFlag != "" {
+			message, err = utils.ReadInput(*inputFlag)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(84)
+			}
+			metadata = payload.NewMetadata(*inputFlag, message)
+		} else {
+			message = []byte(args[2])
+		}
+
+		if err := cypher.Cypher(inFile, outFile, message, cypher.Options{
+			Method:     *methodFlag,
+			FEC:        scheme,
+			Passphrase: passphrase,
+			StegoKey:   *stegoKeyFlag,
+			Strength:   *strengthFlag,
+			Channel:    *channelFlag,
+			Metadata:   metadata,
+			Compress:   *compressFlag,
+			SignKey:    *signKeyFlag,
+			Quality:    *qualityFlag,
+			RatePolicy: ratePolicy,
+		}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
 	} else if *decypherFlag {
 		if len(args) != 1 {
 			utils.DisplayHelp()
@@ -96,6 +217,227 @@ func main() {
 			utils.DisplayHelp()
 			os.Exit(84)
 		}
-		decypher.Decypher(inFile)
+		if err := decypher.Decypher(inFile, decypher.Options{
+			Method:     *methodFlag,
+			Passphrase: passphrase,
+			StegoKey:   *stegoKeyFlag,
+			Output:     *outputFlag,
+			VerifyKey:  *verifyKeyFlag,
+			RatePolicy: ratePolicy,
+		}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
+	} else if *checkFlag {
+		if len(args) != 1 {
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		inFile := args[0]
+
+		if err := utils.CheckFileExists(inFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		frame, err := decypher.Probe(inFile, decypher.Options{
+			Method:     *methodFlag,
+			StegoKey:   *stegoKeyFlag,
+			RatePolicy: ratePolicy,
+		})
+		if errors.Is(err, payload.ErrNoPayload) {
+			fmt.Println("No payload found")
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
+		header := frame.Header
+		fmt.Printf("Payload found: %d bytes (version %d, method %d, channel %d, encrypted %t, signed %t, fec %s, corrected %d)\n",
+			header.Length, header.Version, header.Method, header.Channel, header.Flags&payload.FlagEncrypted != 0,
+			header.Flags&payload.FlagSigned != 0, header.FEC, frame.Corrected)
+	} else if *capacityFlag {
+		if len(args) != 1 {
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		inFile := args[0]
+
+		if err := utils.CheckFileExists(inFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		opts := capacity.Options{
+			FEC:        scheme,
+			Encrypted:  passphrase != "",
+			Signed:     *signKeyFlag != "",
+			StegoKey:   *stegoKeyFlag,
+			Channel:    *channelFlag,
+			Compress:   *compressFlag,
+			RatePolicy: ratePolicy,
+		}
+		if *inputFlag != "" {
+			opts.Message, err = utils.ReadInput(*inputFlag)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(84)
+			}
+			opts.Metadata = payload.NewMetadata(*inputFlag, opts.Message)
+		}
+
+		if err := capacity.Capacity(inFile, opts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
+	} else if *detectFlag {
+		if len(args) != 1 {
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		inFile := args[0]
+
+		if err := utils.CheckFileExists(inFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		if err := detect.Detect(inFile, ratePolicy); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
+	} else if *fskEncodeFlag {
+		expectedArgs := 2
+		if *inputFlag != "" {
+			expectedArgs = 1
+		}
+		if len(args) != expectedArgs {
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		params, err := fsk.NewParams(*bandFlag, *baudFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
+
+		outFile := args[0]
+
+		var message []byte
+		if *inputFlag != "" {
+			message, err = utils.ReadInput(*inputFlag)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(84)
+			}
+		} else {
+			message = []byte(args[1])
+		}
+
+		if err := fsk.Encode(outFile, message, params); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
+	} else if *fskDecodeFlag {
+		if len(args) != 1 {
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		params, err := fsk.NewParams(*bandFlag, *baudFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
+
+		inFile := args[0]
+
+		if err := utils.CheckFileExists(inFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		if err := fsk.Decode(inFile, *outputFlag, params, ratePolicy); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
+	} else if *dtmfEncodeFlag {
+		if len(args) != 2 {
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		if err := dtmf.Encode(args[0], args[1]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
+	} else if *dtmfDecodeFlag {
+		if len(args) != 1 {
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		inFile := args[0]
+
+		if err := utils.CheckFileExists(inFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		if err := dtmf.Decode(inFile, ratePolicy); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
+	} else if *robustnessFlag {
+		if len(args) != 1 {
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		inFile := args[0]
+
+		if err := utils.CheckFileExists(inFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		if err := robustness.Run(inFile, robustness.Options{
+			Method:     *methodFlag,
+			StegoKey:   *stegoKeyFlag,
+			Strength:   *strengthFlag,
+			RatePolicy: ratePolicy,
+		}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
+	} else if *compareFlag {
+		if len(args) != 2 {
+			utils.DisplayHelp()
+			os.Exit(84)
+		}
+
+		for _, inFile := range args {
+			if err := utils.CheckFileExists(inFile); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				utils.DisplayHelp()
+				os.Exit(84)
+			}
+		}
+
+		if err := quality.Compare(args[0], args[1], ratePolicy); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(84)
+		}
 	}
 }
@@ -1,3 +1,5 @@
 module stone-analysis
 
 go 1.21.1
+
+require golang.org/x/crypto v0.33.0
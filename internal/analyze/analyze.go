@@ -6,22 +6,58 @@ import (
 	"stone-analysis/internal/wav"
 )
 
-func Analyze(inFile string, n int) error {
-	wavFile, err := wav.ReadWavFile(inFile)
+func signals(wavFile *wav.WavFile, view string) ([]channelSignal, error) {
+	channels := wavFile.Channels()
+
+	switch view {
+	case "", ViewDownmix:
+		return []channelSignal{{Samples: wav.Downmix(channels)}}, nil
+	case ViewEach:
+		if len(channels) == 1 {
+			return []channelSignal{{Samples: channels[0]}}, nil
+		}
+		result := make([]channelSignal, len(channels))
+		for c, channel := range channels {
+			result[c] = channelSignal{Name: fmt.Sprintf("Channel %d", c+1), Samples: channel}
+		}
+		return result, nil
+	case ViewMidSide:
+		if len(channels) != 2 {
+			return nil, fmt.Errorf("%w: got %d", ErrMidSideChannels, len(channels))
+		}
+		mid, side := wav.MidSide(channels[0], channels[1])
+		return []channelSignal{{Name: "Mid", Samples: mid}, {Name: "Side", Samples: side}}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownView, view)
+}
+
+func Analyze(inFile string, n int, opts Options) error {
+	wavFile, err := wav.ReadWavFile(inFile, opts.RatePolicy)
 	if err != nil {
 		return fmt.Errorf("wav.ReadWavFile(%s): %w", inFile, err)
 	}
 
-	dftResult, err := dft.AnalyzeFrequencies(wavFile.Samples, float64(wavFile.FmtChunk.SampleRate), true)
+	views, err := signals(wavFile, opts.View)
 	if err != nil {
-		return fmt.Errorf("dft.AnalyzeFrequencies(): %w", err)
+		return err
 	}
 
-	topFrequencies := dftResult.GetTopFrequencies(n)
+	for _, view := range views {
+		dftResult, err := dft.AnalyzeFrequencies(view.Samples, float64(wavFile.FmtChunk.SampleRate), true)
+		if err != nil {
+			return fmt.Errorf("dft.AnalyzeFrequencies(): %w", err)
+		}
+
+		topFrequencies := dftResult.GetTopFrequencies(n)
 
-	fmt.Printf("Top %d frequencies:\n", n)
-	for _, freq := range topFrequencies {
-		fmt.Printf("%.1f Hz\n", freq.Frequency)
+		if view.Name != "" {
+			fmt.Printf("%s: ", view.Name)
+		}
+		fmt.Printf("Top %d frequencies:\n", n)
+		for _, freq := range topFrequencies {
+			fmt.Printf("%.1f Hz\n", freq.Frequency)
+		}
 	}
 
 	return nil
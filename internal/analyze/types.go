@@ -1,5 +1,21 @@
 package analyze
 
+import (
+	"errors"
+	"stone-analysis/internal/wav"
+)
+
+const (
+	ViewDownmix = "downmix"
+	ViewEach    = "each"
+	ViewMidSide = "midside"
+)
+
+var (
+	ErrUnknownView     = errors.New("unknown channel view")
+	ErrMidSideChannels = errors.New("mid/side analysis needs exactly two channels")
+)
+
 type FreqMag struct {
 	Freq float64
 	Mag  float64
@@ -11,3 +27,13 @@ type AnalysisResult struct {
 	SampleCount    int
 	WindowUsed     bool
 }
+
+type Options struct {
+	View       string
+	RatePolicy wav.ValidationPolicy
+}
+
+type channelSignal struct {
+	Name    string
+	Samples []float64
+}
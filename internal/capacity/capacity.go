@@ -0,0 +1,120 @@
+package capacity
+
+import (
+	"fmt"
+	"stone-analysis/internal/encryption"
+	"stone-analysis/internal/payload"
+	"stone-analysis/internal/signature"
+	"stone-analysis/internal/stego"
+	"stone-analysis/internal/wav"
+)
+
+func MessageBytes(capacityBits int, opts Options) int {
+	size := payload.MaxDataSize(capacityBits, opts.FEC)
+	if opts.Encrypted {
+		size -= encryption.Overhead
+	}
+	if opts.Signed {
+		size -= signature.Size
+	}
+	return max(size, 0)
+}
+
+func Estimate(wavFile *wav.WavFile, opts Options) ([]MethodCapacity, error) {
+	ratio := 1.0
+	bodySize := 0
+
+	if opts.Message != nil {
+		body, _, err := payload.EncodeBody(opts.Message, opts.Metadata, opts.Compress)
+		if err != nil {
+			return nil, fmt.Errorf("payload.EncodeBody(): %w", err)
+		}
+		bodySize = len(body)
+		if bodySize > 0 {
+			ratio = float64(len(opts.Message)) / float64(bodySize)
+		}
+	}
+
+	layout, err := stego.NewLayout(int(wavFile.FmtChunk.NumChannels), opts.Channel)
+	if err != nil {
+		return nil, fmt.Errorf("stego.NewLayout(): %w", err)
+	}
+
+	methods := stego.All(stego.Config{Key: opts.StegoKey})
+	capacities := make([]MethodCapacity, len(methods))
+
+	for i, method := range methods {
+		carrier := &stego.Carrier{
+			Samples:    layout.Gather(wavFile.Samples, stego.BlockSize(method)),
+			SampleRate: float64(wavFile.FmtChunk.SampleRate),
+			FullScale:  wav.SampleScale(wavFile.FmtChunk),
+		}
+
+		bits := method.Capacity(carrier)
+		size := MessageBytes(bits, opts)
+		capacities[i] = MethodCapacity{
+			Method:         method.Name(),
+			CarrierBits:    bits,
+			MessageBytes:   size,
+			EffectiveBytes: int(float64(size) * ratio),
+			Fits:           bodySize <= size,
+		}
+	}
+
+	return capacities, nil
+}
+
+func Capacity(inFile string, opts Options) error {
+	wavFile, err := wav.ReadWavFile(inFile, opts.RatePolicy)
+	if err != nil {
+		return fmt.Errorf("wav.ReadWavFile(%s): %w", inFile, err)
+	}
+
+	capacities, err := Estimate(wavFile, opts)
+	if err != nil {
+		return err
+	}
+
+	sampleRate := float64(wavFile.FmtChunk.SampleRate)
+	channels := int(wavFile.FmtChunk.NumChannels)
+	frames := len(wavFile.Samples) / channels
+	fmt.Printf("Carrier: %d samples x %d channels at %.0f Hz (%.2f s)\n",
+		frames, channels, sampleRate, float64(frames)/sampleRate)
+
+	overhead := fmt.Sprintf("header %d bytes, fec %s", payload.EncodedHeaderSize(opts.FEC), opts.FEC)
+	if opts.Encrypted {
+		overhead += fmt.Sprintf(", encryption %d bytes", encryption.Overhead)
+	}
+	if opts.Signed {
+		overhead += fmt.Sprintf(", signature %d bytes", signature.Size)
+	}
+	if opts.Compress {
+		overhead += ", compression"
+	}
+	fmt.Printf("Overhead: %s\n", overhead)
+
+	if opts.Message == nil {
+		fmt.Printf("%-10s %12s %14s\n", "METHOD", "CARRIER BITS", "MESSAGE BYTES")
+		for _, c := range capacities {
+			fmt.Printf("%-10s %12d %14d\n", c.Method, c.CarrierBits, c.MessageBytes)
+		}
+		return nil
+	}
+
+	body, _, err := payload.EncodeBody(opts.Message, opts.Metadata, opts.Compress)
+	if err != nil {
+		return fmt.Errorf("payload.EncodeBody(): %w", err)
+	}
+	fmt.Printf("Message: %d bytes, %d bytes to embed\n", len(opts.Message), len(body))
+
+	fmt.Printf("%-10s %12s %14s %15s %5s\n", "METHOD", "CARRIER BITS", "MESSAGE BYTES", "EFFECTIVE BYTES", "FITS")
+	for _, c := range capacities {
+		fits := "no"
+		if c.Fits {
+			fits = "yes"
+		}
+		fmt.Printf("%-10s %12d %14d %15d %5s\n", c.Method, c.CarrierBits, c.MessageBytes, c.EffectiveBytes, fits)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package capacity
+
+import (
+	"stone-analysis/internal/encryption"
+	"stone-analysis/internal/fec"
+	"stone-analysis/internal/payload"
+	"stone-analysis/internal/signature"
+	"testing"
+)
+
+func TestMessageBytes(t *testing.T) {
+	bits := 8 * (payload.EncodedHeaderSize(fec.None) + 100)
+	protected := 8 * (payload.EncodedHeaderSize(fec.Hamming) + 100)
+
+	if n := MessageBytes(bits, Options{}); n != 100 {
+		t.Errorf("Expected 100 bytes without overhead, got %d", n)
+	}
+
+	if n := MessageBytes(bits, Options{Encrypted: true}); n != 100-encryption.Overhead {
+		t.Errorf("Expected %d bytes with encryption, got %d", 100-encryption.Overhead, n)
+	}
+
+	if n := MessageBytes(bits, Options{Signed: true}); n != 100-signature.Size {
+		t.Errorf("Expected %d bytes with a signature, got %d", 100-signature.Size, n)
+	}
+
+	if n := MessageBytes(protected, Options{FEC: fec.Hamming}); n != 57 {
+		t.Errorf("Expected 57 bytes with Hamming, got %d", n)
+	}
+
+	if n := MessageBytes(protected, Options{FEC: fec.ReedSolomon, Encrypted: true}); n != 21 {
+		t.Errorf("Expected 21 bytes with Reed-Solomon and encryption, got %d", n)
+	}
+
+	if n := MessageBytes(64, Options{Encrypted: true}); n != 0 {
+		t.Errorf("Expected no capacity for a tiny carrier, got %d", n)
+	}
+}
@@ -0,0 +1,27 @@
+package capacity
+
+import (
+	"stone-analysis/internal/fec"
+	"stone-analysis/internal/payload"
+	"stone-analysis/internal/wav"
+)
+
+type Options struct {
+	FEC        fec.Scheme
+	Encrypted  bool
+	Signed     bool
+	StegoKey   string
+	Channel    int
+	Compress   bool
+	Message    []byte
+	Metadata   *payload.Metadata
+	RatePolicy wav.ValidationPolicy
+}
+
+type MethodCapacity struct {
+	Method         string
+	CarrierBits    int
+	MessageBytes   int
+	EffectiveBytes int
+	Fits           bool
+}
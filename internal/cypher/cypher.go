@@ -1,11 +1,112 @@
 package cypher
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"stone-analysis/internal/capacity"
+	"stone-analysis/internal/encryption"
+	"stone-analysis/internal/payload"
+	"stone-analysis/internal/quality"
+	"stone-analysis/internal/signature"
+	"stone-analysis/internal/stego"
+	"stone-analysis/internal/wav"
+)
+
+func Cypher(inFile, outFile string, message []byte, opts Options) error {
+	methodName := opts.Method
+	if methodName == "" {
+		methodName = stego.DefaultMethod
+	}
+
+	method, err := stego.New(methodName, stego.Config{
+		Key:      opts.StegoKey,
+		Strength: opts.Strength,
+	})
+	if err != nil {
+		return fmt.Errorf("stego.New(%s): %w", methodName, err)
+	}
+
+	wavFile, err := wav.ReadWavFile(inFile, opts.RatePolicy)
+	if err != nil {
+		return fmt.Errorf("wav.ReadWavFile(%s): %w", inFile, err)
+	}
+
+	layout, err := stego.NewLayout(int(wavFile.FmtChunk.NumChannels), opts.Channel)
+	if err != nil {
+		return fmt.Errorf("stego.NewLayout(): %w", err)
+	}
+
+	carrier := &stego.Carrier{
+		Samples:    layout.Gather(wavFile.Samples, stego.BlockSize(method)),
+		SampleRate: float64(wavFile.FmtChunk.SampleRate),
+		FullScale:  wav.SampleScale(wavFile.FmtChunk),
+	}
+
+	body, flags, err := payload.EncodeBody(message, opts.Metadata, opts.Compress)
+	if err != nil {
+		return fmt.Errorf("payload.EncodeBody(): %w", err)
+	}
+
+	if opts.SignKey != "" {
+		privateKey, err := signature.LoadPrivateKey(opts.SignKey)
+		if err != nil {
+			return fmt.Errorf("signature.LoadPrivateKey(%s): %w", opts.SignKey, err)
+		}
+		body = signature.Sign(body, privateKey)
+		flags |= payload.FlagSigned
+	}
+
+	limit := capacity.MessageBytes(method.Capacity(carrier), capacity.Options{
+		FEC:       opts.FEC,
+		Encrypted: opts.Passphrase != "",
+	})
+	if len(body) > limit {
+		return fmt.Errorf("%w: message is %d bytes, %s can hold %d bytes in %s",
+			ErrMessageTooLarge, len(body), method.Name(), limit, layout)
+	}
+
+	if opts.StegoKey != "" {
+		flags |= payload.FlagKeyedOrder
+	}
+
+	if opts.Passphrase != "" {
+		flags |= payload.FlagEncrypted
+		header := payload.Header{
+			Version: payload.Version,
+			Method:  method.ID(),
+			Channel: uint8(layout.Channel),
+			Flags:   flags,
+			FEC:     opts.FEC,
+		}
+
+		body, err = encryption.Encrypt(body, opts.Passphrase, header.AssociatedData())
+		if err != nil {
+			return fmt.Errorf("encryption.Encrypt(): %w", err)
+		}
+	}
+
+	data, err := payload.Pack(method.ID(), uint8(layout.Channel), flags, opts.FEC, body)
+	if err != nil {
+		return fmt.Errorf("payload.Pack(): %w", err)
+	}
+
+	if err := method.Embed(carrier, stego.BytesToBits(data)); err != nil {
+		return fmt.Errorf("%s.Embed(): %w", method.Name(), err)
+	}
+	layout.Scatter(wavFile.Samples, carrier.Samples, stego.BlockSize(method))
+
+	if err := wav.WriteWavFile(outFile, wavFile); err != nil {
+		return fmt.Errorf("wav.WriteWavFile(%s): %w", outFile, err)
+	}
+
+	if opts.Quality {
+		metrics, fullScale, err := quality.MeasureFiles(inFile, outFile, opts.RatePolicy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Quality metrics unavailable: %v\n", err)
+			return nil
+		}
+		quality.Print(metrics, fullScale)
+	}
 
-func Cypher(inFile, outFile, message string) error {
-	fmt.Printf("Mode: Cypher\n")
-	fmt.Printf("IN_FILE: %s\n", inFile)
-	fmt.Printf("OUT_FILE: %s\n", outFile)
-	fmt.Printf("MESSAGE: %s\n", message)
 	return nil
 }
@@ -0,0 +1,26 @@
+package cypher
+
+import (
+	"errors"
+	"stone-analysis/internal/fec"
+	"stone-analysis/internal/payload"
+	"stone-analysis/internal/wav"
+)
+
+var (
+	ErrMessageTooLarge = errors.New("message does not fit in carrier")
+)
+
+type Options struct {
+	Method     string
+	FEC        fec.Scheme
+	Passphrase string
+	StegoKey   string
+	Strength   float64
+	Channel    int
+	Metadata   *payload.Metadata
+	Compress   bool
+	SignKey    string
+	Quality    bool
+	RatePolicy wav.ValidationPolicy
+}
@@ -1,9 +1,186 @@
 package decypher
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"stone-analysis/internal/encryption"
+	"stone-analysis/internal/fec"
+	"stone-analysis/internal/payload"
+	"stone-analysis/internal/signature"
+	"stone-analysis/internal/stego"
+	"stone-analysis/internal/wav"
+)
+
+func extractWith(method stego.Method, layout stego.Layout, carrier *stego.Carrier) (*payload.Frame, error) {
+	codeBits, err := method.Extract(carrier, 8)
+	if err != nil {
+		return nil, payload.ErrNoPayload
+	}
+
+	scheme, err := payload.ParseScheme(stego.BitsToBytes(codeBits)[0])
+	if err != nil {
+		return nil, err
+	}
+
+	headerSize := payload.EncodedHeaderSize(scheme)
+	headerBits, err := method.Extract(carrier, headerSize*8)
+	if err != nil {
+		return nil, payload.ErrNoPayload
+	}
+
+	header, err := payload.ParseHeader(stego.BitsToBytes(headerBits))
+	if err != nil {
+		return nil, err
+	}
+
+	if header.Method != method.ID() || int(header.Channel) != layout.Channel {
+		return nil, payload.ErrNoPayload
+	}
+
+	capacity := method.Capacity(carrier)/8 - headerSize
+	if uint64(header.Length) > uint64(capacity) {
+		return nil, fmt.Errorf("%w: length %d exceeds carrier capacity %d",
+			payload.ErrCorruptedPayload, header.Length, capacity)
+	}
+
+	bits, err := method.Extract(carrier, (headerSize+int(header.Length))*8)
+	if err != nil {
+		return nil, fmt.Errorf("%s.Extract(): %w", method.Name(), err)
+	}
+
+	return payload.Unpack(stego.BitsToBytes(bits))
+}
+
+func extractPayload(wavFile *wav.WavFile, opts Options) (*payload.Frame, error) {
+	config := stego.Config{Key: opts.StegoKey}
+
+	methods := stego.All(config)
+	if opts.Method != "" {
+		method, err := stego.New(opts.Method, config)
+		if err != nil {
+			return nil, fmt.Errorf("stego.New(%s): %w", opts.Method, err)
+		}
+		methods = []stego.Method{method}
+	}
+
+	for _, layout := range stego.Layouts(int(wavFile.FmtChunk.NumChannels)) {
+		for _, method := range methods {
+			carrier := &stego.Carrier{
+				Samples:    layout.Gather(wavFile.Samples, stego.BlockSize(method)),
+				SampleRate: float64(wavFile.FmtChunk.SampleRate),
+				FullScale:  wav.SampleScale(wavFile.FmtChunk),
+			}
+
+			frame, err := extractWith(method, layout, carrier)
+			if errors.Is(err, payload.ErrNoPayload) {
+				continue
+			}
+			return frame, err
+		}
+	}
+
+	return nil, payload.ErrNoPayload
+}
+
+func Probe(inFile string, opts Options) (*payload.Frame, error) {
+	wavFile, err := wav.ReadWavFile(inFile, opts.RatePolicy)
+	if err != nil {
+		return nil, fmt.Errorf("wav.ReadWavFile(%s): %w", inFile, err)
+	}
+
+	return extractPayload(wavFile, opts)
+}
+
+func Decypher(inFile string, opts Options) error {
+	wavFile, err := wav.ReadWavFile(inFile, opts.RatePolicy)
+	if err != nil {
+		return fmt.Errorf("wav.ReadWavFile(%s): %w", inFile, err)
+	}
+
+	frame, err := extractPayload(wavFile, opts)
+	if err != nil {
+		return err
+	}
+
+	if frame.Header.FEC != fec.None {
+		fmt.Fprintf(os.Stderr, "FEC (%s) corrected %d errors\n", frame.Header.FEC, frame.Corrected)
+	}
+
+	message := frame.Data
+	if frame.Header.Flags&payload.FlagEncrypted != 0 {
+		if opts.Passphrase == "" {
+			return ErrPassphraseRequired
+		}
+
+		message, err = encryption.Decrypt(message, opts.Passphrase, frame.Header.AssociatedData())
+		if err != nil {
+			return fmt.Errorf("encryption.Decrypt(): %w", err)
+		}
+	}
+
+	message, err = verifyMessage(message, frame.Header.Flags, opts.VerifyKey)
+	if err != nil {
+		return err
+	}
+
+	message, metadata, err := payload.DecodeBody(message, frame.Header.Flags)
+	if err != nil {
+		return fmt.Errorf("payload.DecodeBody(): %w", err)
+	}
+
+	if metadata != nil {
+		fmt.Fprintf(os.Stderr, "Recovered file '%s' (%s, %d bytes)\n",
+			metadata.Filename, metadata.MIMEType, len(message))
+	}
+
+	return writeMessage(message, metadata, opts.Output)
+}
+
+func verifyMessage(message []byte, flags uint8, verifyKey string) ([]byte, error) {
+	if flags&payload.FlagSigned == 0 {
+		if verifyKey != "" {
+			return nil, signature.ErrUnsignedPayload
+		}
+		return message, nil
+	}
+
+	message, sig, err := signature.Split(message)
+	if err != nil {
+		return nil, fmt.Errorf("signature.Split(): %w", err)
+	}
+
+	if verifyKey == "" {
+		fmt.Fprintln(os.Stderr, "Payload is signed, use --verify-key to check its authenticity")
+		return message, nil
+	}
+
+	publicKey, err := signature.LoadPublicKey(verifyKey)
+	if err != nil {
+		return nil, fmt.Errorf("signature.LoadPublicKey(%s): %w", verifyKey, err)
+	}
+
+	if err := signature.Verify(message, sig, publicKey); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintln(os.Stderr, "Signature verified, the message is authentic")
+	return message, nil
+}
+
+func writeMessage(message []byte, metadata *payload.Metadata, output string) error {
+	switch {
+	case output == "" && metadata == nil:
+		fmt.Println(string(message))
+	case output == "" || output == "-":
+		if _, err := os.Stdout.Write(message); err != nil {
+			return fmt.Errorf("os.Stdout.Write(): %w", err)
+		}
+	default:
+		if err := os.WriteFile(output, message, 0644); err != nil {
+			return fmt.Errorf("os.WriteFile(%s): %w", output, err)
+		}
+	}
 
-func Decypher(inFile string) error {
-	fmt.Printf("Mode: Decypher\n")
-	fmt.Printf("IN_FILE: %s\n", inFile)
 	return nil
 }
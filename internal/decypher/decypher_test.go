@@ -0,0 +1,52 @@
+package decypher
+
+import (
+	"bytes"
+	"math"
+	"stone-analysis/internal/fec"
+	"stone-analysis/internal/payload"
+	"stone-analysis/internal/stego"
+	"stone-analysis/internal/wav"
+	"testing"
+)
+
+func TestAutoDetectAdaptiveOnQuietCarrier(t *testing.T) {
+	prng := stego.NewPRNG("quiet", "test")
+	samples := make([]float64, 16*1024)
+	for i := range samples {
+		noise := float64(prng.Intn(2001))/1000 - 1
+		samples[i] = math.Round(0.005*noise*32768) / 32768
+	}
+	wavFile := wav.NewWavFile(samples, 48000, 1)
+
+	method, err := stego.New("adaptive", stego.Config{})
+	if err != nil {
+		t.Fatalf("stego.New failed: %v", err)
+	}
+
+	message := []byte("whispered")
+	data, err := payload.Pack(method.ID(), 0, 0, fec.None, message)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	carrier := &stego.Carrier{Samples: wavFile.Samples, SampleRate: 48000, FullScale: 32768}
+	if err := method.Embed(carrier, stego.BytesToBits(data)); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	if _, err := extractWith(stego.NewLSB(""), stego.Layout{Channels: 1}, carrier); err != payload.ErrNoPayload {
+		t.Errorf("Expected lsb to skip the adaptive header, got %v", err)
+	}
+
+	frame, err := extractPayload(wavFile, Options{})
+	if err != nil {
+		t.Fatalf("extractPayload failed: %v", err)
+	}
+	if frame.Header.Method != stego.MethodAdaptive {
+		t.Errorf("Expected method %d, got %d", stego.MethodAdaptive, frame.Header.Method)
+	}
+	if !bytes.Equal(frame.Data, message) {
+		t.Errorf("Expected %q, got %q", message, frame.Data)
+	}
+}
@@ -0,0 +1,19 @@
+package decypher
+
+import (
+	"errors"
+	"stone-analysis/internal/wav"
+)
+
+var (
+	ErrPassphraseRequired = errors.New("payload is encrypted, a passphrase is required")
+)
+
+type Options struct {
+	Method     string
+	Passphrase string
+	StegoKey   string
+	Output     string
+	VerifyKey  string
+	RatePolicy wav.ValidationPolicy
+}
@@ -0,0 +1,139 @@
+package detect
+
+import "math"
+
+func gammaSeries(a, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	sum := 1.0 / a
+	term := sum
+
+	for n := 1; n < 1000; n++ {
+		term *= x / (a + float64(n))
+		sum += term
+		if math.Abs(term) < math.Abs(sum)*1e-14 {
+			break
+		}
+	}
+
+	return sum * math.Exp(-x+a*math.Log(x)-lgamma)
+}
+
+func gammaContinuedFraction(a, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	const tiny = 1e-300
+
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+
+	for i := 1; i < 1000; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-14 {
+			break
+		}
+	}
+
+	return math.Exp(-x+a*math.Log(x)-lgamma) * h
+}
+
+func chiSquareSurvival(chi2 float64, dof int) float64 {
+	if chi2 <= 0 {
+		return 1
+	}
+
+	a := float64(dof) / 2
+	x := chi2 / 2
+
+	if x < a+1 {
+		return 1 - gammaSeries(a, x)
+	}
+	return gammaContinuedFraction(a, x)
+}
+
+func pairOfValues(values []int) (float64, int) {
+	histogram := make(map[int]int)
+	for _, value := range values {
+		histogram[value]++
+	}
+
+	var chi2 float64
+	pairs := 0
+
+	for value, count := range histogram {
+		if value&1 != 0 {
+			continue
+		}
+
+		total := count + histogram[value+1]
+		if total < chiSquareMinCount {
+			continue
+		}
+
+		expected := float64(total) / 2
+		diff := float64(count) - expected
+		chi2 += diff * diff / expected
+		pairs++
+	}
+
+	for value, count := range histogram {
+		if value&1 != 0 && histogram[value-1] == 0 && count >= chiSquareMinCount {
+			chi2 += float64(count) / 2
+			pairs++
+		}
+	}
+
+	return chi2, pairs - 1
+}
+
+func pairOfValuesPValue(values []int) float64 {
+	chi2, dof := pairOfValues(values)
+	if dof < 1 {
+		return 0
+	}
+	return chiSquareSurvival(chi2, dof)
+}
+
+func controlRoughness(values []int) float64 {
+	shifted := make([]int, len(values))
+	for i, value := range values {
+		shifted[i] = value + 1
+	}
+
+	chi2, dof := pairOfValues(shifted)
+	if dof < chiSquareMinPairs {
+		return 0
+	}
+	return chi2 / float64(dof)
+}
+
+func ChiSquare(values []int) ChiSquareResult {
+	result := ChiSquareResult{
+		PValues:   make([]float64, chiSquareSteps),
+		Roughness: controlRoughness(values),
+	}
+
+	for step := 1; step <= chiSquareSteps; step++ {
+		end := len(values) * step / chiSquareSteps
+		pValue := pairOfValuesPValue(values[:end])
+		result.PValues[step-1] = pValue
+
+		if pValue > 0.5 && result.EmbeddedFraction == float64(step-1)/chiSquareSteps {
+			result.EmbeddedFraction = float64(step) / chiSquareSteps
+		}
+	}
+
+	return result
+}
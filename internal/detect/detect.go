@@ -0,0 +1,77 @@
+package detect
+
+import (
+	"fmt"
+	"math"
+	"stone-analysis/internal/wav"
+)
+
+func pcmValues(wavFile *wav.WavFile) []int {
+	fullScale := wav.SampleScale(wavFile.FmtChunk)
+
+	values := make([]int, len(wavFile.Samples))
+	for i, sample := range wavFile.Samples {
+		values[i] = int(math.Round(sample * fullScale))
+	}
+	return values
+}
+
+func inconclusive(flag bool) string {
+	if flag {
+		return " (inconclusive on this cover)"
+	}
+	return ""
+}
+
+func Analyze(values []int) Report {
+	report := Report{
+		SampleCount: len(values),
+		ChiSquare:   ChiSquare(values),
+		RS:          RS(values),
+	}
+
+	chiConfidence := 0.0
+	if report.ChiSquare.Roughness >= chiSquareMinRoughness {
+		chiConfidence = report.ChiSquare.PValues[chiSquareSteps-1]
+		report.Conclusive = true
+	}
+
+	rsConfidence := 0.0
+	if report.RS.Contrast >= rsMinContrast {
+		rsConfidence = (report.RS.EmbeddingRate - rsNoiseFloor) / (rsCertainRate - rsNoiseFloor)
+		rsConfidence = math.Max(0, math.Min(1, rsConfidence))
+		report.Conclusive = true
+	}
+
+	report.Probability = math.Max(chiConfidence, rsConfidence)
+
+	return report
+}
+
+func Detect(inFile string, policy wav.ValidationPolicy) error {
+	wavFile, err := wav.ReadWavFile(inFile, policy)
+	if err != nil {
+		return fmt.Errorf("wav.ReadWavFile(%s): %w", inFile, err)
+	}
+
+	report := Analyze(pcmValues(wavFile))
+
+	fmt.Printf("Samples analyzed: %d\n", report.SampleCount)
+	fmt.Printf("Chi-square cover roughness: %.2f\n", report.ChiSquare.Roughness)
+	fmt.Printf("Chi-square p-value (first %d%%): %.4f\n", 100/chiSquareSteps, report.ChiSquare.PValues[0])
+	fmt.Printf("Chi-square p-value (whole file): %.4f%s\n", report.ChiSquare.PValues[chiSquareSteps-1],
+		inconclusive(report.ChiSquare.Roughness < chiSquareMinRoughness))
+	fmt.Printf("Chi-square embedded fraction: %.1f%%\n", report.ChiSquare.EmbeddedFraction*100)
+	fmt.Printf("RS groups: R(M)=%.4f S(M)=%.4f R(-M)=%.4f S(-M)=%.4f\n",
+		report.RS.RegularM, report.RS.SingularM, report.RS.RegularMinusM, report.RS.SingularMinusM)
+	fmt.Printf("RS contrast: %.1f\n", report.RS.Contrast)
+	fmt.Printf("Estimated embedding rate: %.1f%%%s\n", report.RS.EmbeddingRate*100,
+		inconclusive(report.RS.Contrast < rsMinContrast))
+	if !report.Conclusive {
+		fmt.Println("Probability of hidden data: unknown (no test can discriminate on this cover)")
+		return nil
+	}
+	fmt.Printf("Probability of hidden data: %.1f%%\n", report.Probability*100)
+
+	return nil
+}
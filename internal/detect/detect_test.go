@@ -0,0 +1,186 @@
+package detect
+
+import (
+	"math"
+	"stone-analysis/internal/stego"
+	"testing"
+)
+
+func testCover(n int) []int {
+	prng := stego.NewPRNG("cover", "test")
+	values := make([]int, n)
+	for i := range values {
+		noise := 0.0
+		for k := 0; k < 4; k++ {
+			noise += float64(prng.Intn(2001))/1000 - 1
+		}
+		t := float64(i) / 48000
+		values[i] = int(math.Round(200*math.Sin(2*math.Pi*300*t) + 20*math.Sin(2*math.Pi*2000*t) + 0.6*noise))
+	}
+	return values
+}
+
+func noisyTone(n int, amplitude, sigma float64, seed string) []int {
+	prng := stego.NewPRNG(seed, "tone")
+	values := make([]int, n)
+	for i := range values {
+		noise := 0.0
+		for k := 0; k < 4; k++ {
+			noise += float64(prng.Intn(2001))/1000 - 1
+		}
+		t := float64(i) / 48000
+		value := amplitude*math.Sin(2*math.Pi*440*t) + sigma*math.Sqrt(0.75)*noise
+		values[i] = int(math.Max(-32768, math.Min(32767, math.Round(value))))
+	}
+	return values
+}
+
+func embedRandom(values []int, rate float64) []int {
+	prng := stego.NewPRNG("embed", "test")
+	result := make([]int, len(values))
+	for i, value := range values {
+		result[i] = value
+		if float64(prng.Intn(1000)) < rate*1000 {
+			result[i] = value&^1 | int(prng.Uint64()&1)
+		}
+	}
+	return result
+}
+
+func TestChiSquareSurvival(t *testing.T) {
+	tests := []struct {
+		chi2     float64
+		dof      int
+		expected float64
+	}{
+		{100, 99, 0.4530},
+		{10, 3, 0.0186},
+		{1, 3, 0.8013},
+		{0, 5, 1},
+	}
+
+	for _, test := range tests {
+		result := chiSquareSurvival(test.chi2, test.dof)
+		if math.Abs(result-test.expected) > 1e-3 {
+			t.Errorf("chiSquareSurvival(%.0f, %d): expected %.4f, got %.4f", test.chi2, test.dof, test.expected, result)
+		}
+	}
+}
+
+func TestFlips(t *testing.T) {
+	for _, pair := range [][2]int{{0, 1}, {2, 3}, {-2, -1}} {
+		if flipPositive(pair[0]) != pair[1] || flipPositive(pair[1]) != pair[0] {
+			t.Errorf("flipPositive should swap %d and %d", pair[0], pair[1])
+		}
+	}
+
+	for _, pair := range [][2]int{{-1, 0}, {1, 2}, {-3, -2}} {
+		if flipNegative(pair[0]) != pair[1] || flipNegative(pair[1]) != pair[0] {
+			t.Errorf("flipNegative should swap %d and %d", pair[0], pair[1])
+		}
+	}
+}
+
+func TestCleanCover(t *testing.T) {
+	report := Analyze(testCover(200000))
+
+	if report.ChiSquare.PValues[0] > 0.1 {
+		t.Errorf("Expected low chi-square p-value on a clean cover, got %.3f", report.ChiSquare.PValues[0])
+	}
+	if report.RS.EmbeddingRate > 0.05 {
+		t.Errorf("Expected RS rate near 0 on a clean cover, got %.3f", report.RS.EmbeddingRate)
+	}
+	if report.Probability > 0.2 {
+		t.Errorf("Expected low probability on a clean cover, got %.3f", report.Probability)
+	}
+}
+
+func TestRealisticCleanCovers(t *testing.T) {
+	tests := []struct {
+		amplitude, sigma float64
+	}{
+		{10000, 1},
+		{10000, 5},
+		{10000, 30},
+		{10000, 100},
+		{10000, 300},
+		{1000, 1},
+		{1000, 5},
+		{100, 30},
+		{30, 5},
+		{0.3 * 32767, 0.05 * 32767},
+	}
+
+	for _, test := range tests {
+		for _, seed := range []string{"a", "b"} {
+			report := Analyze(noisyTone(100000, test.amplitude, test.sigma, seed))
+			if report.Probability > 0.2 {
+				t.Errorf("Tone %.0f with noise %.0f (seed %s): expected low probability on a clean cover, got %.3f",
+					test.amplitude, test.sigma, seed, report.Probability)
+			}
+		}
+	}
+}
+
+func TestEmbeddedQuietTone(t *testing.T) {
+	cover := noisyTone(100000, 100, 1, "a")
+
+	report := Analyze(embedRandom(cover, 1))
+	if report.Probability < 0.9 {
+		t.Errorf("Expected high probability for full embedding in a quiet tone, got %.3f", report.Probability)
+	}
+}
+
+func TestEmbeddedLoudTone(t *testing.T) {
+	cover := noisyTone(100000, 10000, 1, "a")
+
+	clean := Analyze(cover)
+	if !clean.Conclusive || clean.Probability > 0.2 {
+		t.Errorf("Expected a conclusive low probability on a clean loud tone, got %v %.3f", clean.Conclusive, clean.Probability)
+	}
+
+	report := Analyze(embedRandom(cover, 0.94))
+	if !report.Conclusive || report.Probability < 0.9 {
+		t.Errorf("Expected a conclusive high probability for embedding in a loud tone, got %v %.3f",
+			report.Conclusive, report.Probability)
+	}
+}
+
+func TestInconclusiveCover(t *testing.T) {
+	cover := noisyTone(100000, 10000, 300, "a")
+
+	for _, values := range [][]int{cover, embedRandom(cover, 0.94)} {
+		report := Analyze(values)
+		if report.Conclusive {
+			t.Errorf("Expected no test to discriminate on a smooth loud cover, got probability %.3f", report.Probability)
+		}
+	}
+}
+
+func TestEmbeddedCover(t *testing.T) {
+	cover := testCover(200000)
+
+	report := Analyze(embedRandom(cover, 0.25))
+	if math.Abs(report.RS.EmbeddingRate-0.25) > 0.08 {
+		t.Errorf("Expected RS rate near 0.25, got %.3f", report.RS.EmbeddingRate)
+	}
+
+	full := Analyze(embedRandom(cover, 1))
+	if full.ChiSquare.PValues[0] < 0.9 {
+		t.Errorf("Expected high chi-square p-value for full embedding, got %.3f", full.ChiSquare.PValues[0])
+	}
+	if full.Probability < 0.9 {
+		t.Errorf("Expected high probability for full embedding, got %.3f", full.Probability)
+	}
+}
+
+func TestSequentialEmbeddedFraction(t *testing.T) {
+	cover := testCover(200000)
+	half := embedRandom(cover[:100000], 1)
+	values := append(half, cover[100000:]...)
+
+	report := Analyze(values)
+	if report.ChiSquare.EmbeddedFraction < 0.3 || report.ChiSquare.EmbeddedFraction > 0.6 {
+		t.Errorf("Expected an embedded fraction near 50%%, got %.2f", report.ChiSquare.EmbeddedFraction)
+	}
+}
@@ -0,0 +1,124 @@
+package detect
+
+import "math"
+
+func flipPositive(value int) int {
+	return value ^ 1
+}
+
+func flipNegative(value int) int {
+	return ((value + 1) ^ 1) - 1
+}
+
+func smoothness(group []int) int {
+	sum := 0
+	for i := 1; i < len(group); i++ {
+		diff := group[i] - group[i-1]
+		if diff < 0 {
+			diff = -diff
+		}
+		sum += diff
+	}
+	return sum
+}
+
+func regularSingular(values []int, mask []int) (float64, float64) {
+	groups := len(values) / rsGroupSize
+	if groups == 0 {
+		return 0, 0
+	}
+
+	regular, singular := 0, 0
+	group := make([]int, rsGroupSize)
+	flipped := make([]int, rsGroupSize)
+
+	for g := 0; g < groups; g++ {
+		copy(group, values[g*rsGroupSize:(g+1)*rsGroupSize])
+
+		for i, value := range group {
+			switch mask[i] {
+			case 1:
+				flipped[i] = flipPositive(value)
+			case -1:
+				flipped[i] = flipNegative(value)
+			default:
+				flipped[i] = value
+			}
+		}
+
+		before := smoothness(group)
+		after := smoothness(flipped)
+		if after > before {
+			regular++
+		} else if after < before {
+			singular++
+		}
+	}
+
+	return float64(regular) / float64(groups), float64(singular) / float64(groups)
+}
+
+func RS(values []int) RSResult {
+	mask := []int{0, 1, 1, 0}
+	minusMask := []int{0, -1, -1, 0}
+
+	rm, sm := regularSingular(values, mask)
+	rmm, smm := regularSingular(values, minusMask)
+
+	inverted := make([]int, len(values))
+	for i, value := range values {
+		inverted[i] = flipPositive(value)
+	}
+	irm, ism := regularSingular(inverted, mask)
+	irmm, ismm := regularSingular(inverted, minusMask)
+
+	d0 := rm - sm
+	d1 := irm - ism
+	dm0 := rmm - smm
+	dm1 := irmm - ismm
+
+	a := 2 * (d1 + d0)
+	b := dm0 - dm1 - d1 - 3*d0
+	c := d0 - dm0
+
+	var x float64
+	if math.Abs(a) < 1e-12 {
+		if math.Abs(b) > 1e-12 {
+			x = -c / b
+		}
+	} else {
+		discriminant := b*b - 4*a*c
+		if discriminant < 0 {
+			discriminant = 0
+		}
+		root := math.Sqrt(discriminant)
+		x1 := (-b + root) / (2 * a)
+		x2 := (-b - root) / (2 * a)
+		x = x1
+		if math.Abs(x2) < math.Abs(x1) {
+			x = x2
+		}
+	}
+
+	rate := 0.0
+	if math.Abs(x-0.5) > 1e-12 {
+		rate = x / (x - 0.5)
+	}
+
+	contrast := 0.0
+	if groups := len(values) / rsGroupSize; groups > 0 {
+		stderr := math.Sqrt(math.Max(rmm+smm-dm0*dm0, 0) / float64(groups))
+		if stderr > 0 {
+			contrast = dm0 / stderr
+		}
+	}
+
+	return RSResult{
+		RegularM:       rm,
+		SingularM:      sm,
+		RegularMinusM:  rmm,
+		SingularMinusM: smm,
+		Contrast:       contrast,
+		EmbeddingRate:  math.Max(0, math.Min(1, rate)),
+	}
+}
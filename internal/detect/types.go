@@ -0,0 +1,35 @@
+package detect
+
+const (
+	chiSquareSteps        = 20
+	chiSquareMinCount     = 10
+	chiSquareMinPairs     = 10
+	chiSquareMinRoughness = 1.5
+	rsGroupSize           = 4
+	rsMinContrast         = 8
+	rsNoiseFloor          = 0.25
+	rsCertainRate         = 0.6
+)
+
+type ChiSquareResult struct {
+	PValues          []float64
+	Roughness        float64
+	EmbeddedFraction float64
+}
+
+type RSResult struct {
+	RegularM       float64
+	SingularM      float64
+	RegularMinusM  float64
+	SingularMinusM float64
+	Contrast       float64
+	EmbeddingRate  float64
+}
+
+type Report struct {
+	SampleCount int
+	ChiSquare   ChiSquareResult
+	RS          RSResult
+	Conclusive  bool
+	Probability float64
+}
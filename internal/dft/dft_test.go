@@ -433,3 +433,23 @@ func BenchmarkDFT1024(b *testing.B) {
 		}
 	}
 }
+
+func TestGoertzel(t *testing.T) {
+	const sampleRate = 48000.0
+	samples := make([]float64, 480)
+	for i := range samples {
+		samples[i] = 0.5 * math.Sin(2*math.Pi*1200*float64(i)/sampleRate)
+	}
+
+	if amplitude := Goertzel(samples, 1200, sampleRate); math.Abs(amplitude-0.5) > 0.01 {
+		t.Errorf("Expected amplitude 0.5 at 1200 Hz, got %.4f", amplitude)
+	}
+
+	if amplitude := Goertzel(samples, 2200, sampleRate); amplitude > 0.01 {
+		t.Errorf("Expected no energy at 2200 Hz, got %.4f", amplitude)
+	}
+
+	if amplitude := Goertzel(nil, 1200, sampleRate); amplitude != 0 {
+		t.Errorf("Expected 0 for empty input, got %.4f", amplitude)
+	}
+}
@@ -0,0 +1,24 @@
+package dft
+
+import "math"
+
+func Goertzel(samples []float64, frequency, sampleRate float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	omega := 2 * math.Pi * frequency / sampleRate
+	coeff := 2 * math.Cos(omega)
+
+	var s1, s2 float64
+	for _, sample := range samples {
+		s0 := sample + coeff*s1 - s2
+		s2 = s1
+		s1 = s0
+	}
+
+	real := s1 - s2*math.Cos(omega)
+	imag := s2 * math.Sin(omega)
+
+	return 2 * math.Sqrt(real*real+imag*imag) / float64(len(samples))
+}
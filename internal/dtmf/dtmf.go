@@ -0,0 +1,151 @@
+package dtmf
+
+import (
+	"fmt"
+	"math"
+	"stone-analysis/internal/dft"
+	"stone-analysis/internal/wav"
+	"strings"
+)
+
+func frequencies(digit rune) (float64, float64, error) {
+	index := strings.IndexRune(keys, digit)
+	if index < 0 {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidDigit, digit)
+	}
+	return rowFrequencies[index/4], columnFrequencies[index%4], nil
+}
+
+func Synthesize(digits string, sampleRate float64) ([]float64, error) {
+	digits = strings.ToUpper(digits)
+	if digits == "" {
+		return nil, ErrNoDigits
+	}
+
+	toneLength := int(toneDuration * sampleRate)
+	pauseLength := int(pauseDuration * sampleRate)
+	lead := int(silence * sampleRate)
+	rampLength := int(ramp * sampleRate)
+
+	samples := make([]float64, lead, 2*lead+len(digits)*(toneLength+pauseLength))
+
+	for _, digit := range digits {
+		row, column, err := frequencies(digit)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < toneLength; i++ {
+			t := float64(i) / sampleRate
+			gain := toneAmplitude
+			if edge := min(i, toneLength-1-i); edge < rampLength {
+				gain *= 0.5 - 0.5*math.Cos(math.Pi*float64(edge)/float64(rampLength))
+			}
+			samples = append(samples, gain*(math.Sin(2*math.Pi*row*t)+math.Sin(2*math.Pi*column*t)))
+		}
+		samples = append(samples, make([]float64, pauseLength)...)
+	}
+
+	return append(samples, make([]float64, lead-pauseLength)...), nil
+}
+
+func strongest(frame []float64, candidates [4]float64, sampleRate float64) (int, float64, float64) {
+	best, bestLevel, secondLevel := 0, 0.0, 0.0
+	for i, frequency := range candidates {
+		level := dft.Goertzel(frame, frequency, sampleRate)
+		switch {
+		case level > bestLevel:
+			best, bestLevel, secondLevel = i, level, bestLevel
+		case level > secondLevel:
+			secondLevel = level
+		}
+	}
+	return best, bestLevel, secondLevel
+}
+
+func detectFrame(frame []float64, sampleRate float64) (byte, bool) {
+	row, rowLevel, rowSecond := strongest(frame, rowFrequencies, sampleRate)
+	column, columnLevel, columnSecond := strongest(frame, columnFrequencies, sampleRate)
+
+	if rowLevel < minLevel || columnLevel < minLevel {
+		return 0, false
+	}
+
+	if rowLevel < minPeakGap*rowSecond || columnLevel < minPeakGap*columnSecond {
+		return 0, false
+	}
+
+	twist := 20 * math.Log10(columnLevel/rowLevel)
+	if twist > maxTwistDB || twist < -maxReverseDB {
+		return 0, false
+	}
+
+	var energy float64
+	for _, sample := range frame {
+		energy += sample * sample
+	}
+	if (rowLevel*rowLevel+columnLevel*columnLevel)/2 < minPurity*energy/float64(len(frame)) {
+		return 0, false
+	}
+
+	return keys[row*4+column], true
+}
+
+func Detect(samples []float64, sampleRate float64) string {
+	windowLength := int(window * sampleRate)
+	hopLength := int(hop * sampleRate)
+
+	var digits strings.Builder
+	var current byte
+	frames, misses := 0, 0
+
+	for start := 0; start+windowLength <= len(samples); start += hopLength {
+		digit, ok := detectFrame(samples[start:start+windowLength], sampleRate)
+
+		if ok && digit == current {
+			frames++
+			misses = 0
+			if frames == minFrames {
+				digits.WriteByte(digit)
+			}
+			continue
+		}
+
+		if current != 0 {
+			misses++
+			if misses < maxMisses && !ok {
+				continue
+			}
+		}
+
+		current, frames, misses = 0, 0, 0
+		if ok {
+			current, frames = digit, 1
+		}
+	}
+
+	return digits.String()
+}
+
+func Encode(digits, outFile string) error {
+	samples, err := Synthesize(digits, SampleRate)
+	if err != nil {
+		return err
+	}
+
+	if err := wav.WriteWavFile(outFile, wav.NewWavFile(samples, SampleRate, 1)); err != nil {
+		return fmt.Errorf("wav.WriteWavFile(%s): %w", outFile, err)
+	}
+
+	return nil
+}
+
+func Decode(inFile string, policy wav.ValidationPolicy) error {
+	wavFile, err := wav.ReadWavFile(inFile, policy)
+	if err != nil {
+		return fmt.Errorf("wav.ReadWavFile(%s): %w", inFile, err)
+	}
+
+	fmt.Println(Detect(wavFile.Channel(0), float64(wavFile.FmtChunk.SampleRate)))
+	return nil
+}
@@ -0,0 +1,76 @@
+package dtmf
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func tone(samples []float64, digit rune, duration, rowGain, columnGain, sampleRate float64) []float64 {
+	row, column, _ := frequencies(digit)
+	for i := 0; i < int(duration*sampleRate); i++ {
+		t := float64(i) / sampleRate
+		samples = append(samples, 0.2*rowGain*math.Sin(2*math.Pi*row*t)+0.2*columnGain*math.Sin(2*math.Pi*column*t))
+	}
+	return samples
+}
+
+func TestSynthesizeDetect(t *testing.T) {
+	digits := "123A456B789C*0#D"
+
+	samples, err := Synthesize(digits, SampleRate)
+	if err != nil {
+		t.Fatalf("Synthesize failed: %v", err)
+	}
+
+	if result := Detect(samples, SampleRate); result != digits {
+		t.Errorf("Expected %q, got %q", digits, result)
+	}
+
+	if _, err := Synthesize("12X", SampleRate); !errors.Is(err, ErrInvalidDigit) {
+		t.Errorf("Expected ErrInvalidDigit, got %v", err)
+	}
+}
+
+func TestDetectTolerances(t *testing.T) {
+	const sampleRate = 8000.0
+	rng := rand.New(rand.NewSource(7))
+
+	var samples []float64
+	samples = tone(samples, '5', 0.045, 1, 1, sampleRate)
+	samples = append(samples, make([]float64, int(0.045*sampleRate))...)
+	samples = tone(samples, '5', 0.06, 1, 2.2, sampleRate)
+	samples = append(samples, make([]float64, int(0.05*sampleRate))...)
+	samples = tone(samples, '9', 0.2, 1.5, 1, sampleRate)
+	samples = append(samples, make([]float64, int(0.1*sampleRate))...)
+	samples = tone(samples, '#', 0.08, 1, 1, sampleRate)
+
+	for i := range samples {
+		samples[i] += 0.01 * rng.NormFloat64()
+	}
+
+	if result := Detect(samples, sampleRate); result != "559#" {
+		t.Errorf("Expected %q, got %q", "559#", result)
+	}
+}
+
+func TestDetectRejectsInvalidTones(t *testing.T) {
+	var samples []float64
+	samples = tone(samples, '1', 0.2, 1, 4, SampleRate)
+	samples = append(samples, make([]float64, SampleRate/10)...)
+	samples = tone(samples, '2', 0.2, 1, 0.4, SampleRate)
+	samples = append(samples, make([]float64, SampleRate/10)...)
+	samples = tone(samples, '3', 0.015, 1, 1, SampleRate)
+
+	speech := make([]float64, SampleRate/2)
+	for i := range speech {
+		t := float64(i) / SampleRate
+		speech[i] = 0.2*math.Sin(2*math.Pi*180*t) + 0.1*math.Sin(2*math.Pi*360*t) + 0.05*math.Sin(2*math.Pi*540*t)
+	}
+	samples = append(samples, speech...)
+
+	if result := Detect(samples, SampleRate); result != "" {
+		t.Errorf("Expected no digits, got %q", result)
+	}
+}
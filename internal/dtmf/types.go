@@ -0,0 +1,35 @@
+package dtmf
+
+import "errors"
+
+const (
+	SampleRate = 48000
+
+	toneDuration  = 0.1
+	pauseDuration = 0.1
+	silence       = 0.2
+	ramp          = 0.003
+	toneAmplitude = 0.25
+
+	window       = 0.02
+	hop          = 0.01
+	minFrames    = 2
+	maxMisses    = 2
+	minPeakGap   = 2.0
+	minPurity    = 0.5
+	minLevel     = 1e-3
+	maxTwistDB   = 8.0
+	maxReverseDB = 4.0
+)
+
+const keys = "123A456B789C*0#D"
+
+var (
+	rowFrequencies    = [4]float64{697, 770, 852, 941}
+	columnFrequencies = [4]float64{1209, 1336, 1477, 1633}
+)
+
+var (
+	ErrInvalidDigit = errors.New("invalid DTMF digit")
+	ErrNoDigits     = errors.New("no DTMF digits to encode")
+)
@@ -0,0 +1,86 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"golang.org/x/crypto/scrypt"
+)
+
+func newAEAD(passphrase string, salt []byte, logN, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<logN, r, p, KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("scrypt.Key(): %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher(): %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cipher.NewGCM(): %w", err)
+	}
+
+	return aead, nil
+}
+
+func validParams(logN, r, p int) bool {
+	if logN < 1 || logN > maxLogN || r < 1 || r > maxR || p < 1 || p > maxP {
+		return false
+	}
+	return 128*r*(1<<logN) <= maxMemory
+}
+
+func Encrypt(plaintext []byte, passphrase string, additionalData []byte) ([]byte, error) {
+	sealed := make([]byte, paramsSize+SaltSize+NonceSize, Overhead+len(plaintext))
+	sealed[0] = DefaultLogN
+	sealed[1] = DefaultR
+	sealed[2] = DefaultP
+
+	salt := sealed[paramsSize : paramsSize+SaltSize]
+	nonce := sealed[paramsSize+SaltSize:]
+
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("rand.Read(salt): %w", err)
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("rand.Read(nonce): %w", err)
+	}
+
+	aead, err := newAEAD(passphrase, salt, DefaultLogN, DefaultR, DefaultP)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(sealed, nonce, plaintext, additionalData), nil
+}
+
+func Decrypt(sealed []byte, passphrase string, additionalData []byte) ([]byte, error) {
+	if len(sealed) < Overhead {
+		return nil, ErrSealedTooShort
+	}
+
+	logN, r, p := int(sealed[0]), int(sealed[1]), int(sealed[2])
+	if !validParams(logN, r, p) {
+		return nil, ErrInvalidScryptParams
+	}
+
+	salt := sealed[paramsSize : paramsSize+SaltSize]
+	nonce := sealed[paramsSize+SaltSize : paramsSize+SaltSize+NonceSize]
+	ciphertext := sealed[paramsSize+SaltSize+NonceSize:]
+
+	aead, err := newAEAD(passphrase, salt, logN, r, p)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,75 @@
+package encryption
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	plaintext := []byte("the quick brown fox")
+
+	sealed, err := Encrypt(plaintext, "correct horse", []byte("header"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	if len(sealed) != len(plaintext)+Overhead {
+		t.Errorf("Expected %d bytes, got %d", len(plaintext)+Overhead, len(sealed))
+	}
+
+	if bytes.Contains(sealed, plaintext) {
+		t.Error("Sealed payload contains the plaintext")
+	}
+
+	result, err := Decrypt(sealed, "correct horse", []byte("header"))
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if !bytes.Equal(result, plaintext) {
+		t.Errorf("Expected %q, got %q", plaintext, result)
+	}
+
+	_, err = Decrypt(sealed, "wrong horse", []byte("header"))
+	if err != ErrWrongPassphrase {
+		t.Errorf("Expected ErrWrongPassphrase, got %v", err)
+	}
+
+	_, err = Decrypt(sealed, "correct horse", []byte("tampered"))
+	if err != ErrWrongPassphrase {
+		t.Errorf("Expected ErrWrongPassphrase for tampered associated data, got %v", err)
+	}
+
+	_, err = Decrypt(sealed[:Overhead-1], "correct horse", []byte("header"))
+	if err != ErrSealedTooShort {
+		t.Errorf("Expected ErrSealedTooShort, got %v", err)
+	}
+}
+
+func TestDecryptHostileParams(t *testing.T) {
+	sealed, err := Encrypt([]byte("payload"), "correct horse", nil)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	tests := []struct {
+		logN, r, p byte
+	}{
+		{20, 255, 1},
+		{20, 8, 1},
+		{15, 0, 1},
+		{15, 8, 0},
+		{15, 8, 255},
+		{0, 8, 1},
+		{21, 1, 1},
+	}
+
+	for _, test := range tests {
+		hostile := append([]byte(nil), sealed...)
+		hostile[0], hostile[1], hostile[2] = test.logN, test.r, test.p
+
+		_, err := Decrypt(hostile, "correct horse", nil)
+		if err != ErrInvalidScryptParams {
+			t.Errorf("Decrypt(logN=%d, r=%d, p=%d): expected ErrInvalidScryptParams, got %v", test.logN, test.r, test.p, err)
+		}
+	}
+}
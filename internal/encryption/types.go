@@ -0,0 +1,28 @@
+package encryption
+
+import "errors"
+
+const (
+	SaltSize  = 16
+	NonceSize = 12
+	TagSize   = 16
+	KeySize   = 32
+
+	paramsSize = 3
+	Overhead   = paramsSize + SaltSize + NonceSize + TagSize
+
+	DefaultLogN = 15
+	DefaultR    = 8
+	DefaultP    = 1
+
+	maxLogN   = 20
+	maxR      = 32
+	maxP      = 16
+	maxMemory = 256 << 20
+)
+
+var (
+	ErrInvalidScryptParams = errors.New("invalid scrypt parameters")
+	ErrSealedTooShort      = errors.New("encrypted payload is too short")
+	ErrWrongPassphrase     = errors.New("wrong passphrase or tampered payload")
+)
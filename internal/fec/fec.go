@@ -0,0 +1,80 @@
+package fec
+
+import "fmt"
+
+func ParseScheme(name string) (Scheme, error) {
+	switch name {
+	case "", "none":
+		return None, nil
+	case "hamming":
+		return Hamming, nil
+	case "rs":
+		return ReedSolomon, nil
+	}
+	return None, fmt.Errorf("%w: %s", ErrUnknownScheme, name)
+}
+
+func (s Scheme) String() string {
+	switch s {
+	case None:
+		return "none"
+	case Hamming:
+		return "hamming"
+	case ReedSolomon:
+		return "rs"
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(s))
+}
+
+func EncodedSize(scheme Scheme, n int) int {
+	switch scheme {
+	case Hamming:
+		return hammingEncodedSize(n)
+	case ReedSolomon:
+		return rsEncodedSize(n)
+	}
+	return n
+}
+
+func MaxDataSize(scheme Scheme, encodedSize int) int {
+	if encodedSize <= 0 {
+		return 0
+	}
+
+	switch scheme {
+	case Hamming:
+		return encodedSize * 8 / 14
+	case ReedSolomon:
+		size := encodedSize / rsBlockSize * rsDataSize
+		if rest := encodedSize % rsBlockSize; rest > rsParitySize {
+			size += rest - rsParitySize
+		}
+		return size
+	}
+	return encodedSize
+}
+
+func Encode(scheme Scheme, data []byte) ([]byte, error) {
+	switch scheme {
+	case None:
+		return data, nil
+	case Hamming:
+		return Interleave(hammingEncode(data)), nil
+	case ReedSolomon:
+		return Interleave(rsEncode(data)), nil
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnknownScheme, uint8(scheme))
+}
+
+func Decode(scheme Scheme, encoded []byte) ([]byte, int, error) {
+	switch scheme {
+	case None:
+		return encoded, 0, nil
+	case Hamming:
+		data, corrected := hammingDecode(Deinterleave(encoded))
+		return data, corrected, nil
+	case ReedSolomon:
+		return rsDecode(Deinterleave(encoded))
+	}
+	return nil, 0, fmt.Errorf("%w: %d", ErrUnknownScheme, uint8(scheme))
+}
@@ -0,0 +1,157 @@
+package fec
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func testData(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i*37 + 11)
+	}
+	return data
+}
+
+func flipBit(data []byte, i int) {
+	data[i/8] ^= 1 << (7 - i%8)
+}
+
+func TestInterleaveRoundTrip(t *testing.T) {
+	data := testData(100)
+
+	interleaved := Interleave(data)
+	if bytes.Equal(interleaved, data) {
+		t.Error("Interleave should reorder bits")
+	}
+
+	if result := Deinterleave(interleaved); !bytes.Equal(result, data) {
+		t.Errorf("Deinterleave did not restore the data")
+	}
+}
+
+func TestHammingCorrectsSingleErrors(t *testing.T) {
+	data := testData(100)
+
+	encoded, err := Encode(Hamming, data)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if len(encoded) != EncodedSize(Hamming, len(data)) {
+		t.Errorf("Expected %d encoded bytes, got %d", EncodedSize(Hamming, len(data)), len(encoded))
+	}
+
+	for i := 0; i < 20; i++ {
+		flipBit(encoded, i)
+	}
+
+	result, corrected, err := Decode(Hamming, encoded)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !bytes.Equal(result, data) {
+		t.Errorf("Hamming did not recover a 20-bit burst")
+	}
+	if corrected != 20 {
+		t.Errorf("Expected 20 corrected errors, got %d", corrected)
+	}
+}
+
+func TestHammingSizes(t *testing.T) {
+	for n := 0; n < 64; n++ {
+		data := testData(n)
+		encoded, _ := Encode(Hamming, data)
+		result, _, _ := Decode(Hamming, encoded)
+		if !bytes.Equal(result, data) {
+			t.Errorf("Round trip failed for %d bytes", n)
+		}
+	}
+}
+
+func TestReedSolomonCorrectsErrors(t *testing.T) {
+	data := testData(500)
+
+	encoded, err := Encode(ReedSolomon, data)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if len(encoded) != EncodedSize(ReedSolomon, len(data)) {
+		t.Errorf("Expected %d encoded bytes, got %d", EncodedSize(ReedSolomon, len(data)), len(encoded))
+	}
+
+	for i := 0; i < 30; i++ {
+		flipBit(encoded, i*131)
+	}
+
+	result, corrected, err := Decode(ReedSolomon, encoded)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !bytes.Equal(result, data) {
+		t.Errorf("Reed-Solomon did not recover the data")
+	}
+	if corrected != 30 {
+		t.Errorf("Expected 30 corrected errors, got %d", corrected)
+	}
+}
+
+func TestReedSolomonTooManyErrors(t *testing.T) {
+	data := testData(100)
+	encoded := rsEncode(data)
+
+	for i := 0; i < 40; i++ {
+		encoded[i] ^= 0xFF
+	}
+
+	_, _, err := rsDecode(encoded)
+	if !errors.Is(err, ErrTooManyErrors) {
+		t.Errorf("Expected ErrTooManyErrors, got %v", err)
+	}
+}
+
+func TestReedSolomonShortBlocks(t *testing.T) {
+	for _, n := range []int{1, 10, 223, 224, 446, 447} {
+		data := testData(n)
+		encoded := rsEncode(data)
+		encoded[len(encoded)-1] ^= 0x5A
+		encoded[0] ^= 0x01
+
+		result, _, err := rsDecode(encoded)
+		if err != nil {
+			t.Fatalf("rsDecode(%d bytes) failed: %v", n, err)
+		}
+		if !bytes.Equal(result, data) {
+			t.Errorf("Round trip failed for %d bytes", n)
+		}
+	}
+}
+
+func TestParseScheme(t *testing.T) {
+	for _, scheme := range []Scheme{None, Hamming, ReedSolomon} {
+		parsed, err := ParseScheme(scheme.String())
+		if err != nil || parsed != scheme {
+			t.Errorf("ParseScheme(%s) = %v, %v", scheme, parsed, err)
+		}
+	}
+
+	if _, err := ParseScheme("turbo"); !errors.Is(err, ErrUnknownScheme) {
+		t.Errorf("Expected ErrUnknownScheme, got %v", err)
+	}
+}
+
+func TestMaxDataSize(t *testing.T) {
+	for _, scheme := range []Scheme{None, Hamming, ReedSolomon} {
+		for encoded := 0; encoded < 1000; encoded++ {
+			n := MaxDataSize(scheme, encoded)
+			if n > 0 && EncodedSize(scheme, n) > encoded {
+				t.Fatalf("%s: %d data bytes need %d encoded bytes, more than %d",
+					scheme, n, EncodedSize(scheme, n), encoded)
+			}
+			if EncodedSize(scheme, n+1) <= encoded {
+				t.Fatalf("%s: %d encoded bytes could hold %d data bytes, not only %d",
+					scheme, encoded, n+1, n)
+			}
+		}
+	}
+}
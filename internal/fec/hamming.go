@@ -0,0 +1,79 @@
+package fec
+
+func hammingEncodeNibble(nibble byte) byte {
+	d1 := (nibble >> 3) & 1
+	d2 := (nibble >> 2) & 1
+	d3 := (nibble >> 1) & 1
+	d4 := nibble & 1
+
+	p1 := d1 ^ d2 ^ d4
+	p2 := d1 ^ d3 ^ d4
+	p3 := d2 ^ d3 ^ d4
+
+	return p1<<6 | p2<<5 | d1<<4 | p3<<3 | d2<<2 | d3<<1 | d4
+}
+
+func hammingDecodeCodeword(codeword byte) (byte, bool) {
+	bit := func(position int) byte {
+		return (codeword >> (7 - position)) & 1
+	}
+
+	s1 := bit(1) ^ bit(3) ^ bit(5) ^ bit(7)
+	s2 := bit(2) ^ bit(3) ^ bit(6) ^ bit(7)
+	s3 := bit(4) ^ bit(5) ^ bit(6) ^ bit(7)
+
+	syndrome := int(s3<<2 | s2<<1 | s1)
+	corrected := syndrome != 0
+	if corrected {
+		codeword ^= 1 << (7 - syndrome)
+	}
+
+	return bit(3)<<3 | bit(5)<<2 | bit(6)<<1 | bit(7), corrected
+}
+
+func hammingEncodedSize(n int) int {
+	return (n*14 + 7) / 8
+}
+
+func hammingEncode(data []byte) []byte {
+	encoded := make([]byte, hammingEncodedSize(len(data)))
+
+	position := 0
+	for _, b := range data {
+		for _, nibble := range []byte{b >> 4, b & 0x0F} {
+			codeword := hammingEncodeNibble(nibble)
+			for i := 6; i >= 0; i-- {
+				setBit(encoded, position, (codeword>>i)&1)
+				position++
+			}
+		}
+	}
+
+	return encoded
+}
+
+func hammingDecode(encoded []byte) ([]byte, int) {
+	data := make([]byte, len(encoded)*8/14)
+	corrected := 0
+
+	position := 0
+	for i := range data {
+		var value byte
+		for half := 0; half < 2; half++ {
+			var codeword byte
+			for j := 0; j < 7; j++ {
+				codeword = codeword<<1 | getBit(encoded, position)
+				position++
+			}
+
+			nibble, fixed := hammingDecodeCodeword(codeword)
+			if fixed {
+				corrected++
+			}
+			value = value<<4 | nibble
+		}
+		data[i] = value
+	}
+
+	return data, corrected
+}
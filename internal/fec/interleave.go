@@ -0,0 +1,35 @@
+package fec
+
+func interleaveOrder(n int) []int {
+	order := make([]int, 0, n)
+	for column := 0; column < interleaveDepth; column++ {
+		for i := column; i < n; i += interleaveDepth {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+func getBit(data []byte, i int) byte {
+	return (data[i/8] >> (7 - i%8)) & 1
+}
+
+func setBit(data []byte, i int, bit byte) {
+	data[i/8] |= bit << (7 - i%8)
+}
+
+func Interleave(data []byte) []byte {
+	result := make([]byte, len(data))
+	for position, source := range interleaveOrder(len(data) * 8) {
+		setBit(result, position, getBit(data, source))
+	}
+	return result
+}
+
+func Deinterleave(data []byte) []byte {
+	result := make([]byte, len(data))
+	for position, target := range interleaveOrder(len(data) * 8) {
+		setBit(result, target, getBit(data, position))
+	}
+	return result
+}
@@ -0,0 +1,302 @@
+package fec
+
+var (
+	gfExp [512]byte
+	gfLog [256]int
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < 512; i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+gfLog[b]]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[(gfLog[a]+255-gfLog[b])%255]
+}
+
+func gfPow(a byte, power int) byte {
+	exponent := (gfLog[a] * power) % 255
+	if exponent < 0 {
+		exponent += 255
+	}
+	return gfExp[exponent]
+}
+
+func gfInverse(a byte) byte {
+	return gfExp[255-gfLog[a]]
+}
+
+func polyScale(p []byte, x byte) []byte {
+	result := make([]byte, len(p))
+	for i, coef := range p {
+		result[i] = gfMul(coef, x)
+	}
+	return result
+}
+
+func polyAdd(p, q []byte) []byte {
+	size := len(p)
+	if len(q) > size {
+		size = len(q)
+	}
+
+	result := make([]byte, size)
+	for i, coef := range p {
+		result[i+size-len(p)] = coef
+	}
+	for i, coef := range q {
+		result[i+size-len(q)] ^= coef
+	}
+	return result
+}
+
+func polyMul(p, q []byte) []byte {
+	result := make([]byte, len(p)+len(q)-1)
+	for j, qc := range q {
+		for i, pc := range p {
+			result[i+j] ^= gfMul(pc, qc)
+		}
+	}
+	return result
+}
+
+func polyEval(p []byte, x byte) byte {
+	y := p[0]
+	for _, coef := range p[1:] {
+		y = gfMul(y, x) ^ coef
+	}
+	return y
+}
+
+func polyRemainder(dividend, divisor []byte) []byte {
+	result := make([]byte, len(dividend))
+	copy(result, dividend)
+
+	for i := 0; i < len(dividend)-(len(divisor)-1); i++ {
+		coef := result[i]
+		if coef == 0 {
+			continue
+		}
+		for j := 1; j < len(divisor); j++ {
+			if divisor[j] != 0 {
+				result[i+j] ^= gfMul(divisor[j], coef)
+			}
+		}
+	}
+
+	return result[len(result)-(len(divisor)-1):]
+}
+
+func rsGenerator(nsym int) []byte {
+	g := []byte{1}
+	for i := 0; i < nsym; i++ {
+		g = polyMul(g, []byte{1, gfPow(2, i)})
+	}
+	return g
+}
+
+func rsEncodeBlock(data []byte, nsym int) []byte {
+	padded := make([]byte, len(data)+nsym)
+	copy(padded, data)
+
+	parity := polyRemainder(padded, rsGenerator(nsym))
+
+	codeword := make([]byte, len(data)+nsym)
+	copy(codeword, data)
+	copy(codeword[len(data):], parity)
+	return codeword
+}
+
+func rsSyndromes(codeword []byte, nsym int) ([]byte, bool) {
+	syndromes := make([]byte, nsym+1)
+	clean := true
+	for i := 0; i < nsym; i++ {
+		syndromes[i+1] = polyEval(codeword, gfPow(2, i))
+		if syndromes[i+1] != 0 {
+			clean = false
+		}
+	}
+	return syndromes, clean
+}
+
+func rsErrorLocator(syndromes []byte, nsym int) ([]byte, error) {
+	errLoc := []byte{1}
+	oldLoc := []byte{1}
+	shift := len(syndromes) - nsym
+
+	for i := 0; i < nsym; i++ {
+		k := i + shift
+		delta := syndromes[k]
+		for j := 1; j < len(errLoc); j++ {
+			delta ^= gfMul(errLoc[len(errLoc)-(j+1)], syndromes[k-j])
+		}
+
+		oldLoc = append(oldLoc, 0)
+		if delta != 0 {
+			if len(oldLoc) > len(errLoc) {
+				newLoc := polyScale(oldLoc, delta)
+				oldLoc = polyScale(errLoc, gfInverse(delta))
+				errLoc = newLoc
+			}
+			errLoc = polyAdd(errLoc, polyScale(oldLoc, delta))
+		}
+	}
+
+	for len(errLoc) > 0 && errLoc[0] == 0 {
+		errLoc = errLoc[1:]
+	}
+
+	if (len(errLoc)-1)*2 > nsym {
+		return nil, ErrTooManyErrors
+	}
+
+	return errLoc, nil
+}
+
+func rsErrorPositions(errLoc []byte, length int) ([]int, error) {
+	reversed := make([]byte, len(errLoc))
+	for i, coef := range errLoc {
+		reversed[len(errLoc)-1-i] = coef
+	}
+
+	var positions []int
+	for i := 0; i < length; i++ {
+		if polyEval(reversed, gfPow(2, i)) == 0 {
+			positions = append(positions, length-1-i)
+		}
+	}
+
+	if len(positions) != len(errLoc)-1 {
+		return nil, ErrTooManyErrors
+	}
+
+	return positions, nil
+}
+
+func rsCorrectErrata(codeword, syndromes []byte, positions []int) []byte {
+	coefPositions := make([]int, len(positions))
+	for i, position := range positions {
+		coefPositions[i] = len(codeword) - 1 - position
+	}
+
+	errLoc := []byte{1}
+	for _, position := range coefPositions {
+		errLoc = polyMul(errLoc, polyAdd([]byte{1}, []byte{gfPow(2, position), 0}))
+	}
+
+	reversedSyndromes := make([]byte, len(syndromes))
+	for i, s := range syndromes {
+		reversedSyndromes[len(syndromes)-1-i] = s
+	}
+
+	divisor := make([]byte, len(errLoc)+1)
+	divisor[0] = 1
+	errEval := polyRemainder(polyMul(reversedSyndromes, errLoc), divisor)
+
+	x := make([]byte, len(coefPositions))
+	for i, position := range coefPositions {
+		x[i] = gfPow(2, -(255 - position))
+	}
+
+	magnitudes := make([]byte, len(codeword))
+	for i, xi := range x {
+		xiInverse := gfInverse(xi)
+
+		var errLocPrime byte = 1
+		for j, xj := range x {
+			if j != i {
+				errLocPrime = gfMul(errLocPrime, 1^gfMul(xiInverse, xj))
+			}
+		}
+
+		y := gfMul(xi, polyEval(errEval, xiInverse))
+		magnitudes[positions[i]] = gfDiv(y, errLocPrime)
+	}
+
+	return polyAdd(codeword, magnitudes)
+}
+
+func rsDecodeBlock(codeword []byte, nsym int) ([]byte, int, error) {
+	syndromes, clean := rsSyndromes(codeword, nsym)
+	if clean {
+		return codeword[:len(codeword)-nsym], 0, nil
+	}
+
+	errLoc, err := rsErrorLocator(syndromes, nsym)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	positions, err := rsErrorPositions(errLoc, len(codeword))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	corrected := rsCorrectErrata(codeword, syndromes, positions)
+	if _, clean := rsSyndromes(corrected, nsym); !clean {
+		return nil, 0, ErrTooManyErrors
+	}
+
+	return corrected[:len(corrected)-nsym], len(positions), nil
+}
+
+func rsEncodedSize(n int) int {
+	blocks := (n + rsDataSize - 1) / rsDataSize
+	return n + blocks*rsParitySize
+}
+
+func rsEncode(data []byte) []byte {
+	encoded := make([]byte, 0, rsEncodedSize(len(data)))
+	for start := 0; start < len(data); start += rsDataSize {
+		end := start + rsDataSize
+		if end > len(data) {
+			end = len(data)
+		}
+		encoded = append(encoded, rsEncodeBlock(data[start:end], rsParitySize)...)
+	}
+	return encoded
+}
+
+func rsDecode(encoded []byte) ([]byte, int, error) {
+	data := make([]byte, 0, len(encoded))
+	corrected := 0
+
+	for start := 0; start < len(encoded); start += rsBlockSize {
+		end := start + rsBlockSize
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		if end-start <= rsParitySize {
+			return nil, 0, ErrInvalidCodeword
+		}
+
+		block, fixed, err := rsDecodeBlock(encoded[start:end], rsParitySize)
+		if err != nil {
+			return nil, 0, err
+		}
+		data = append(data, block...)
+		corrected += fixed
+	}
+
+	return data, corrected, nil
+}
@@ -0,0 +1,24 @@
+package fec
+
+import "errors"
+
+type Scheme uint8
+
+const (
+	None Scheme = iota
+	Hamming
+	ReedSolomon
+)
+
+const (
+	interleaveDepth = 64
+	rsParitySize    = 32
+	rsBlockSize     = 255
+	rsDataSize      = rsBlockSize - rsParitySize
+)
+
+var (
+	ErrUnknownScheme   = errors.New("unknown error correction scheme")
+	ErrTooManyErrors   = errors.New("too many errors to correct")
+	ErrInvalidCodeword = errors.New("invalid codeword length")
+)
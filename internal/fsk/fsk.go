@@ -0,0 +1,264 @@
+package fsk
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"sort"
+	"stone-analysis/internal/dft"
+	"stone-analysis/internal/fec"
+	"stone-analysis/internal/stego"
+	"stone-analysis/internal/wav"
+)
+
+func Bands() []string {
+	names := make([]string, 0, len(bands))
+	for name := range bands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func NewParams(band string, baud float64) (Params, error) {
+	params, ok := bands[band]
+	if !ok {
+		return Params{}, fmt.Errorf("%w: %s", ErrUnknownBand, band)
+	}
+
+	if baud != 0 {
+		params.Baud = baud
+	}
+	if params.Baud <= 0 || params.Baud > params.Mark-params.Space {
+		return Params{}, fmt.Errorf("%w: %g", ErrInvalidBaud, params.Baud)
+	}
+
+	return params, nil
+}
+
+func symbolPeriod(params Params, sampleRate float64) float64 {
+	return sampleRate / params.Baud
+}
+
+func frameBits(data []byte) ([]uint8, error) {
+	if len(data) > MaxMessageSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrMessageTooLarge, len(data))
+	}
+
+	length := make([]byte, lengthSize)
+	binary.BigEndian.PutUint16(length, uint16(len(data)))
+	encodedLength, err := fec.Encode(fec.Hamming, length)
+	if err != nil {
+		return nil, fmt.Errorf("fec.Encode(): %w", err)
+	}
+
+	body := binary.BigEndian.AppendUint32(append([]byte{}, data...), crc32.ChecksumIEEE(data))
+	encodedBody, err := fec.Encode(fec.Hamming, body)
+	if err != nil {
+		return nil, fmt.Errorf("fec.Encode(): %w", err)
+	}
+
+	bits := make([]uint8, 0, preambleBits+syncBits+8*(len(encodedLength)+len(encodedBody)))
+	for i := 0; i < preambleBits; i++ {
+		bits = append(bits, uint8(1-i%2))
+	}
+	for i := syncBits - 1; i >= 0; i-- {
+		bits = append(bits, uint8(syncWord>>i&1))
+	}
+	bits = append(bits, stego.BytesToBits(encodedLength)...)
+	return append(bits, stego.BytesToBits(encodedBody)...), nil
+}
+
+func Modulate(data []byte, params Params, sampleRate float64) ([]float64, error) {
+	bits, err := frameBits(data)
+	if err != nil {
+		return nil, err
+	}
+
+	period := symbolPeriod(params, sampleRate)
+	lead := int(silence * sampleRate)
+	rampLength := int(ramp * sampleRate)
+	toneLength := int(math.Round(float64(len(bits)) * period))
+
+	samples := make([]float64, lead+toneLength+lead)
+	phase := 0.0
+
+	for i := 0; i < toneLength; i++ {
+		frequency := params.Space
+		if bits[min(int(float64(i)/period), len(bits)-1)] == 1 {
+			frequency = params.Mark
+		}
+
+		gain := amplitude
+		if edge := min(i, toneLength-1-i); edge < rampLength {
+			gain *= 0.5 - 0.5*math.Cos(math.Pi*float64(edge)/float64(rampLength))
+		}
+
+		samples[lead+i] = gain * math.Sin(phase)
+		phase = math.Mod(phase+2*math.Pi*frequency/sampleRate, 2*math.Pi)
+	}
+
+	return samples, nil
+}
+
+func softDecisions(samples []float64, params Params, sampleRate float64) []float64 {
+	period := symbolPeriod(params, sampleRate)
+	symbol := int(math.Round(period))
+	hop := period / oversample
+	if len(samples) < symbol {
+		return nil
+	}
+
+	decisions := make([]float64, int(float64(len(samples)-symbol)/hop)+1)
+	for j := range decisions {
+		start := min(int(math.Round(float64(j)*hop)), len(samples)-symbol)
+		window := samples[start : start+symbol]
+		mark := dft.Goertzel(window, params.Mark, sampleRate)
+		space := dft.Goertzel(window, params.Space, sampleRate)
+		decisions[j] = (mark - space) / (mark + space + 1e-12)
+	}
+
+	return decisions
+}
+
+func syncPattern() []uint8 {
+	pattern := make([]uint8, 0, patternBits)
+	for i := 0; i < patternBits-syncBits; i++ {
+		pattern = append(pattern, uint8(1-i%2))
+	}
+	for i := syncBits - 1; i >= 0; i-- {
+		pattern = append(pattern, uint8(syncWord>>i&1))
+	}
+	return pattern
+}
+
+func matchSync(decisions []float64, start int, pattern []uint8) (float64, bool) {
+	score := 0.0
+	for k, bit := range pattern {
+		soft := decisions[start+k*oversample]
+		if (soft > 0) != (bit == 1) {
+			return 0, false
+		}
+		score += math.Abs(soft)
+	}
+	return score, true
+}
+
+func readBytes(decisions []float64, start, count int) ([]byte, bool) {
+	if start+(count*8-1)*oversample >= len(decisions) {
+		return nil, false
+	}
+
+	bits := make([]uint8, count*8)
+	for k := range bits {
+		if decisions[start+k*oversample] > 0 {
+			bits[k] = 1
+		}
+	}
+	return stego.BitsToBytes(bits), true
+}
+
+func decodeFrame(decisions []float64, start int) ([]byte, error) {
+	lengthBytes := fec.EncodedSize(fec.Hamming, lengthSize)
+	encodedLength, ok := readBytes(decisions, start, lengthBytes)
+	if !ok {
+		return nil, ErrNoTransmission
+	}
+
+	length, _, err := fec.Decode(fec.Hamming, encodedLength)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fec.Decode(): %v", ErrCorruptedMessage, err)
+	}
+
+	bodySize := fec.EncodedSize(fec.Hamming, int(binary.BigEndian.Uint16(length))+checksumSize)
+	encodedBody, ok := readBytes(decisions, start+lengthBytes*8*oversample, bodySize)
+	if !ok {
+		return nil, fmt.Errorf("%w: transmission is truncated", ErrCorruptedMessage)
+	}
+
+	body, _, err := fec.Decode(fec.Hamming, encodedBody)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fec.Decode(): %v", ErrCorruptedMessage, err)
+	}
+
+	data := body[:len(body)-checksumSize]
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(body[len(body)-checksumSize:]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorruptedMessage)
+	}
+
+	return data, nil
+}
+
+func Demodulate(samples []float64, params Params, sampleRate float64) ([]byte, error) {
+	decisions := softDecisions(samples, params, sampleRate)
+	pattern := syncPattern()
+	span := (patternBits - 1) * oversample
+
+	err := ErrNoTransmission
+	for j := 0; j+span+oversample < len(decisions); j++ {
+		if _, ok := matchSync(decisions, j, pattern); !ok {
+			continue
+		}
+
+		best, bestScore := j, 0.0
+		for offset := j; offset < j+oversample; offset++ {
+			if score, ok := matchSync(decisions, offset, pattern); ok && score > bestScore {
+				best, bestScore = offset, score
+			}
+		}
+
+		data, frameErr := decodeFrame(decisions, best+patternBits*oversample)
+		if frameErr == nil {
+			return data, nil
+		}
+		err = frameErr
+		j = best + oversample
+	}
+
+	return nil, err
+}
+
+func Encode(outFile string, data []byte, params Params) error {
+	samples, err := Modulate(data, params, SampleRate)
+	if err != nil {
+		return err
+	}
+
+	if err := wav.WriteWavFile(outFile, wav.NewWavFile(samples, SampleRate, 1)); err != nil {
+		return fmt.Errorf("wav.WriteWavFile(%s): %w", outFile, err)
+	}
+
+	return nil
+}
+
+func Decode(inFile, output string, params Params, policy wav.ValidationPolicy) error {
+	wavFile, err := wav.ReadWavFile(inFile, policy)
+	if err != nil {
+		return fmt.Errorf("wav.ReadWavFile(%s): %w", inFile, err)
+	}
+
+	data, err := Demodulate(wavFile.Channel(0), params, float64(wavFile.FmtChunk.SampleRate))
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if output == "-" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			return fmt.Errorf("os.Stdout.Write(): %w", err)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("os.WriteFile(%s): %w", output, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package fsk
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestModulateDemodulate(t *testing.T) {
+	message := []byte("acoustic data transfer")
+
+	for _, band := range Bands() {
+		params, err := NewParams(band, 0)
+		if err != nil {
+			t.Fatalf("NewParams(%s) failed: %v", band, err)
+		}
+
+		samples, err := Modulate(message, params, SampleRate)
+		if err != nil {
+			t.Fatalf("Modulate failed: %v", err)
+		}
+
+		data, err := Demodulate(samples, params, SampleRate)
+		if err != nil {
+			t.Fatalf("%s: Demodulate failed: %v", band, err)
+		}
+		if !bytes.Equal(data, message) {
+			t.Errorf("%s: expected %q, got %q", band, message, data)
+		}
+	}
+}
+
+func TestDemodulateRecording(t *testing.T) {
+	message := []byte("played through a speaker")
+	params, _ := NewParams("audible", 0)
+
+	samples, err := Modulate(message, params, SampleRate)
+	if err != nil {
+		t.Fatalf("Modulate failed: %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	recording := make([]float64, 3217, len(samples)+6000)
+	for _, sample := range samples {
+		recording = append(recording, 0.3*sample)
+	}
+	recording = append(recording, make([]float64, 2783)...)
+	previous := 0.0
+	for i := range recording {
+		current := recording[i] + 0.03*rng.NormFloat64() + 0.05*math.Sin(2*math.Pi*50*float64(i)/SampleRate)
+		recording[i] = 0.7*current + 0.3*previous
+		previous = current
+	}
+
+	data, err := Demodulate(recording, params, SampleRate)
+	if err != nil {
+		t.Fatalf("Demodulate failed: %v", err)
+	}
+	if !bytes.Equal(data, message) {
+		t.Errorf("Expected %q, got %q", message, data)
+	}
+}
+
+func TestDemodulateFractionalSymbols(t *testing.T) {
+	message := []byte("a longer message sent at a sample rate that does not divide the baud rate evenly")
+	params, _ := NewParams("audible", 0)
+
+	samples, err := Modulate(message, params, SampleRate)
+	if err != nil {
+		t.Fatalf("Modulate failed: %v", err)
+	}
+
+	const sampleRate = 44100
+	resampled := make([]float64, len(samples)*sampleRate/SampleRate)
+	for i := range resampled {
+		position := float64(i) * SampleRate / sampleRate
+		index := int(position)
+		frac := position - float64(index)
+		resampled[i] = samples[index] * (1 - frac)
+		if index+1 < len(samples) {
+			resampled[i] += samples[index+1] * frac
+		}
+	}
+
+	data, err := Demodulate(resampled, params, sampleRate)
+	if err != nil {
+		t.Fatalf("Demodulate failed: %v", err)
+	}
+	if !bytes.Equal(data, message) {
+		t.Errorf("Expected %q, got %q", message, data)
+	}
+
+	direct, err := Modulate(message, params, sampleRate)
+	if err != nil {
+		t.Fatalf("Modulate failed: %v", err)
+	}
+	if data, err := Demodulate(direct, params, sampleRate); err != nil || !bytes.Equal(data, message) {
+		t.Errorf("Expected %q at %d Hz, got %q (%v)", message, sampleRate, data, err)
+	}
+}
+
+func TestDemodulateSilence(t *testing.T) {
+	params, _ := NewParams("audible", 0)
+
+	if _, err := Demodulate(make([]float64, SampleRate), params, SampleRate); !errors.Is(err, ErrNoTransmission) {
+		t.Errorf("Expected ErrNoTransmission, got %v", err)
+	}
+}
+
+func TestNewParams(t *testing.T) {
+	if _, err := NewParams("radio", 0); !errors.Is(err, ErrUnknownBand) {
+		t.Errorf("Expected ErrUnknownBand, got %v", err)
+	}
+
+	if _, err := NewParams("audible", 5000); !errors.Is(err, ErrInvalidBaud) {
+		t.Errorf("Expected ErrInvalidBaud, got %v", err)
+	}
+
+	params, err := NewParams("ultrasonic", 250)
+	if err != nil || params.Baud != 250 {
+		t.Errorf("Expected baud 250, got %+v (%v)", params, err)
+	}
+}
@@ -0,0 +1,41 @@
+package fsk
+
+import "errors"
+
+const (
+	SampleRate  = 48000
+	DefaultBaud = 100
+
+	amplitude    = 0.5
+	oversample   = 8
+	preambleBits = 32
+	syncBits     = 16
+	patternBits  = 32
+	silence      = 0.1
+	ramp         = 0.005
+	lengthSize   = 2
+	checksumSize = 4
+
+	MaxMessageSize = 1<<16 - 1
+)
+
+const syncWord uint16 = 0x2DD4
+
+var (
+	ErrUnknownBand      = errors.New("unknown FSK band")
+	ErrInvalidBaud      = errors.New("invalid FSK baud rate")
+	ErrMessageTooLarge  = errors.New("message is too large for an FSK frame")
+	ErrNoTransmission   = errors.New("no FSK transmission found")
+	ErrCorruptedMessage = errors.New("FSK message is corrupted")
+)
+
+type Params struct {
+	Space float64
+	Mark  float64
+	Baud  float64
+}
+
+var bands = map[string]Params{
+	"audible":    {Space: 1200, Mark: 2200, Baud: DefaultBaud},
+	"ultrasonic": {Space: 18500, Mark: 19500, Baud: DefaultBaud},
+}
@@ -0,0 +1,44 @@
+package payload
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+)
+
+func Compress(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	writer, err := flate.NewWriter(&buffer, flate.BestCompression)
+	if err != nil {
+		return nil, fmt.Errorf("flate.NewWriter(): %w", err)
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("writer.Write(): %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("writer.Close(): %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func Decompress(data []byte) ([]byte, error) {
+	reader := flate.NewReader(bytes.NewReader(data))
+	defer reader.Close()
+
+	result, err := io.ReadAll(io.LimitReader(reader, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: io.ReadAll(): %v", ErrCorruptedPayload, err)
+	}
+
+	if len(result) > maxDecompressedSize {
+		return nil, fmt.Errorf("%w: decompressed payload exceeds %d bytes",
+			ErrCorruptedPayload, maxDecompressedSize)
+	}
+
+	return result, nil
+}
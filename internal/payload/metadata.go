@@ -0,0 +1,65 @@
+package payload
+
+import (
+	"encoding/binary"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+)
+
+func NewMetadata(filename string, content []byte) *Metadata {
+	name := ""
+	if filename != "" && filename != "-" {
+		name = filepath.Base(filename)
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(name))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(content)
+	}
+
+	return &Metadata{Filename: name, MIMEType: mimeType}
+}
+
+func EncodeMetadata(metadata *Metadata, content []byte) ([]byte, error) {
+	if len(metadata.Filename) > maxFilenameSize || len(metadata.MIMEType) > maxMIMETypeSize {
+		return nil, ErrMetadataTooLarge
+	}
+
+	data := make([]byte, 0, metadata.Size()+len(content))
+	data = binary.BigEndian.AppendUint16(data, uint16(len(metadata.Filename)))
+	data = append(data, metadata.Filename...)
+	data = append(data, uint8(len(metadata.MIMEType)))
+	data = append(data, metadata.MIMEType...)
+	data = append(data, content...)
+
+	return data, nil
+}
+
+func DecodeMetadata(data []byte) (*Metadata, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, fmt.Errorf("%w: metadata is truncated", ErrCorruptedPayload)
+	}
+
+	nameSize := int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+	if len(data) < nameSize+1 {
+		return nil, nil, fmt.Errorf("%w: metadata is truncated", ErrCorruptedPayload)
+	}
+	name := string(data[:nameSize])
+	data = data[nameSize:]
+
+	mimeSize := int(data[0])
+	data = data[1:]
+	if len(data) < mimeSize {
+		return nil, nil, fmt.Errorf("%w: metadata is truncated", ErrCorruptedPayload)
+	}
+	mimeType := string(data[:mimeSize])
+
+	return &Metadata{Filename: name, MIMEType: mimeType}, data[mimeSize:], nil
+}
+
+func (m *Metadata) Size() int {
+	return 2 + len(m.Filename) + 1 + len(m.MIMEType)
+}
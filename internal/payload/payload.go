@@ -0,0 +1,191 @@
+package payload
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math/bits"
+	"stone-analysis/internal/fec"
+)
+
+func headerScheme(scheme fec.Scheme) fec.Scheme {
+	if scheme == fec.None {
+		return fec.None
+	}
+	return fec.Hamming
+}
+
+func EncodedHeaderSize(scheme fec.Scheme) int {
+	return 1 + fec.EncodedSize(headerScheme(scheme), HeaderSize)
+}
+
+func ParseScheme(code byte) (fec.Scheme, error) {
+	for scheme, expected := range schemeCodes {
+		if bits.OnesCount8(code^expected) <= 1 {
+			return scheme, nil
+		}
+	}
+	return fec.None, ErrNoPayload
+}
+
+func (h Header) AssociatedData() []byte {
+	data := []byte(Magic)
+	return append(data, h.Version, h.Method, h.Channel, h.Flags, uint8(h.FEC))
+}
+
+func Pack(method, channel, flags uint8, scheme fec.Scheme, data []byte) ([]byte, error) {
+	code, ok := schemeCodes[scheme]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", fec.ErrUnknownScheme, uint8(scheme))
+	}
+
+	body, err := fec.Encode(scheme, data)
+	if err != nil {
+		return nil, fmt.Errorf("fec.Encode(): %w", err)
+	}
+
+	header := make([]byte, HeaderSize)
+	copy(header[0:4], Magic)
+	header[4] = Version
+	header[5] = method
+	header[6] = channel
+	header[7] = flags
+	binary.BigEndian.PutUint32(header[8:12], uint32(len(body)))
+	binary.BigEndian.PutUint32(header[12:16], crc32.ChecksumIEEE(data))
+
+	encodedHeader, err := fec.Encode(headerScheme(scheme), header)
+	if err != nil {
+		return nil, fmt.Errorf("fec.Encode(): %w", err)
+	}
+
+	raw := append([]byte{code}, encodedHeader...)
+	return append(raw, body...), nil
+}
+
+func decodeHeader(raw []byte) (Header, int, error) {
+	if len(raw) == 0 {
+		return Header{}, 0, ErrNoPayload
+	}
+
+	scheme, err := ParseScheme(raw[0])
+	if err != nil {
+		return Header{}, 0, err
+	}
+
+	size := EncodedHeaderSize(scheme)
+	if len(raw) < size {
+		return Header{}, 0, ErrNoPayload
+	}
+
+	decoded, corrected, err := fec.Decode(headerScheme(scheme), raw[1:size])
+	if err != nil || string(decoded[0:4]) != Magic {
+		return Header{}, 0, ErrNoPayload
+	}
+	if raw[0] != schemeCodes[scheme] {
+		corrected++
+	}
+
+	header := Header{
+		Version:  decoded[4],
+		Method:   decoded[5],
+		Channel:  decoded[6],
+		Flags:    decoded[7],
+		FEC:      scheme,
+		Length:   binary.BigEndian.Uint32(decoded[8:12]),
+		Checksum: binary.BigEndian.Uint32(decoded[12:16]),
+	}
+
+	if header.Version != Version {
+		return Header{}, 0, fmt.Errorf("%w: %d", ErrUnsupportedVersion, header.Version)
+	}
+
+	return header, corrected, nil
+}
+
+func ParseHeader(raw []byte) (Header, error) {
+	header, _, err := decodeHeader(raw)
+	return header, err
+}
+
+func Unpack(raw []byte) (*Frame, error) {
+	header, corrected, err := decodeHeader(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	headerSize := EncodedHeaderSize(header.FEC)
+	available := len(raw) - headerSize
+	if uint64(header.Length) > uint64(available) {
+		return nil, fmt.Errorf("%w: length %d exceeds available %d bytes",
+			ErrCorruptedPayload, header.Length, available)
+	}
+
+	body := raw[headerSize : headerSize+int(header.Length)]
+	data, fixed, err := fec.Decode(header.FEC, body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fec.Decode(): %v", ErrCorruptedPayload, err)
+	}
+
+	if crc32.ChecksumIEEE(data) != header.Checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorruptedPayload)
+	}
+
+	return &Frame{
+		Header:    header,
+		Data:      data,
+		Corrected: corrected + fixed,
+	}, nil
+}
+
+func MaxDataSize(capacityBits int, scheme fec.Scheme) int {
+	return fec.MaxDataSize(scheme, capacityBits/8-EncodedHeaderSize(scheme))
+}
+
+func EncodeBody(content []byte, metadata *Metadata, compress bool) ([]byte, uint8, error) {
+	body := content
+	var flags uint8
+
+	if metadata != nil {
+		encoded, err := EncodeMetadata(metadata, content)
+		if err != nil {
+			return nil, 0, fmt.Errorf("EncodeMetadata(): %w", err)
+		}
+		body = encoded
+		flags |= FlagMetadata
+	}
+
+	if compress {
+		compressed, err := Compress(body)
+		if err != nil {
+			return nil, 0, fmt.Errorf("Compress(): %w", err)
+		}
+		if len(compressed) < len(body) {
+			body = compressed
+			flags |= FlagCompressed
+		}
+	}
+
+	return body, flags, nil
+}
+
+func DecodeBody(body []byte, flags uint8) ([]byte, *Metadata, error) {
+	var err error
+
+	if flags&FlagCompressed != 0 {
+		body, err = Decompress(body)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if flags&FlagMetadata == 0 {
+		return body, nil, nil
+	}
+
+	metadata, content, err := DecodeMetadata(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return content, metadata, nil
+}
@@ -0,0 +1,252 @@
+package payload
+
+import (
+	"bytes"
+	"errors"
+	"stone-analysis/internal/fec"
+	"testing"
+)
+
+func mustPack(t *testing.T, method, flags uint8, scheme fec.Scheme, data []byte) []byte {
+	raw, err := Pack(method, 0, flags, scheme, data)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	return raw
+}
+
+func TestPackUnpack(t *testing.T) {
+	data := []byte("hello stone")
+
+	raw := mustPack(t, 3, FlagEncrypted, fec.None, data)
+	if len(raw) != HeaderSize+1+len(data) {
+		t.Fatalf("Expected %d bytes, got %d", HeaderSize+1+len(data), len(raw))
+	}
+
+	frame, err := Unpack(raw)
+	if err != nil {
+		t.Fatalf("Unpack failed: %v", err)
+	}
+
+	header := frame.Header
+	if header.Version != Version || header.Method != 3 || header.Flags != FlagEncrypted ||
+		header.FEC != fec.None || header.Length != uint32(len(data)) {
+		t.Errorf("Unexpected header %+v", header)
+	}
+
+	if !bytes.Equal(frame.Data, data) {
+		t.Errorf("Expected %q, got %q", data, frame.Data)
+	}
+}
+
+func TestPackChannel(t *testing.T) {
+	raw, err := Pack(1, 2, 0, fec.None, []byte("right"))
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	header, err := ParseHeader(raw)
+	if err != nil {
+		t.Fatalf("ParseHeader failed: %v", err)
+	}
+	if header.Channel != 2 {
+		t.Errorf("Expected channel 2, got %d", header.Channel)
+	}
+}
+
+func TestUnpackTrailingBytes(t *testing.T) {
+	raw := append(mustPack(t, 1, 0, fec.None, []byte("abc")), 0xFF, 0xFF)
+
+	frame, err := Unpack(raw)
+	if err != nil {
+		t.Fatalf("Unpack failed: %v", err)
+	}
+	if string(frame.Data) != "abc" {
+		t.Errorf("Expected 'abc', got %q", frame.Data)
+	}
+}
+
+func TestUnpackCorrectsErrors(t *testing.T) {
+	data := []byte("forward error correction keeps this message intact")
+
+	for _, scheme := range []fec.Scheme{fec.Hamming, fec.ReedSolomon} {
+		raw := mustPack(t, 1, 0, scheme, data)
+		raw[0] ^= 0x04
+		raw[3] ^= 0x10
+		raw[EncodedHeaderSize(scheme)+5] ^= 0x01
+		raw[len(raw)-1] ^= 0x80
+
+		frame, err := Unpack(raw)
+		if err != nil {
+			t.Fatalf("%s: Unpack failed: %v", scheme, err)
+		}
+		if !bytes.Equal(frame.Data, data) {
+			t.Errorf("%s: expected %q, got %q", scheme, data, frame.Data)
+		}
+		if frame.Corrected != 4 {
+			t.Errorf("%s: expected 4 corrected errors, got %d", scheme, frame.Corrected)
+		}
+		if frame.Header.FEC != scheme {
+			t.Errorf("Expected scheme %s in header, got %s", scheme, frame.Header.FEC)
+		}
+	}
+}
+
+func TestUnpackErrors(t *testing.T) {
+	_, err := Unpack(make([]byte, 64))
+	if !errors.Is(err, ErrNoPayload) {
+		t.Errorf("Expected ErrNoPayload, got %v", err)
+	}
+
+	_, err = Unpack([]byte("STNA"))
+	if !errors.Is(err, ErrNoPayload) {
+		t.Errorf("Expected ErrNoPayload for short input, got %v", err)
+	}
+
+	corrupted := mustPack(t, 1, 0, fec.None, []byte("message"))
+	corrupted[EncodedHeaderSize(fec.None)] ^= 0x01
+	_, err = Unpack(corrupted)
+	if !errors.Is(err, ErrCorruptedPayload) {
+		t.Errorf("Expected ErrCorruptedPayload for checksum mismatch, got %v", err)
+	}
+
+	truncated := mustPack(t, 1, 0, fec.None, []byte("message"))
+	_, err = Unpack(truncated[:len(truncated)-2])
+	if !errors.Is(err, ErrCorruptedPayload) {
+		t.Errorf("Expected ErrCorruptedPayload for truncated payload, got %v", err)
+	}
+
+	future := mustPack(t, 1, 0, fec.None, []byte("message"))
+	future[5] = Version + 1
+	_, err = Unpack(future)
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Expected ErrUnsupportedVersion, got %v", err)
+	}
+
+	stale := mustPack(t, 1, 0, fec.None, []byte("message"))
+	stale[5] = Version - 1
+	_, err = Unpack(stale)
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Expected ErrUnsupportedVersion for stale header, got %v", err)
+	}
+}
+
+func TestHeaderOverhead(t *testing.T) {
+	if n := EncodedHeaderSize(fec.None); n != HeaderSize+1 {
+		t.Errorf("Expected %d header bytes without FEC, got %d", HeaderSize+1, n)
+	}
+
+	for _, scheme := range []fec.Scheme{fec.Hamming, fec.ReedSolomon} {
+		raw := mustPack(t, 1, 0, scheme, []byte("x"))
+		if _, err := ParseScheme(raw[0]); err != nil {
+			t.Errorf("%s: ParseScheme failed: %v", scheme, err)
+		}
+		if n := EncodedHeaderSize(scheme); n != 1+fec.EncodedSize(fec.Hamming, HeaderSize) {
+			t.Errorf("%s: expected Hamming-protected header, got %d bytes", scheme, n)
+		}
+	}
+
+	if _, err := ParseScheme(0x55); !errors.Is(err, ErrNoPayload) {
+		t.Errorf("Expected ErrNoPayload for unknown scheme code, got %v", err)
+	}
+}
+
+func TestMetadataRoundTrip(t *testing.T) {
+	content := []byte{0x89, 'P', 'N', 'G', 0x00, 0x01}
+	metadata := NewMetadata("/tmp/keys/signature.png", content)
+
+	if metadata.Filename != "signature.png" || metadata.MIMEType != "image/png" {
+		t.Errorf("Unexpected metadata %+v", metadata)
+	}
+
+	data, err := EncodeMetadata(metadata, content)
+	if err != nil {
+		t.Fatalf("EncodeMetadata failed: %v", err)
+	}
+	if len(data) != metadata.Size()+len(content) {
+		t.Errorf("Expected %d bytes, got %d", metadata.Size()+len(content), len(data))
+	}
+
+	decoded, result, err := DecodeMetadata(data)
+	if err != nil {
+		t.Fatalf("DecodeMetadata failed: %v", err)
+	}
+	if *decoded != *metadata {
+		t.Errorf("Expected %+v, got %+v", metadata, decoded)
+	}
+	if !bytes.Equal(result, content) {
+		t.Errorf("Expected %v, got %v", content, result)
+	}
+
+	if _, _, err := DecodeMetadata(data[:5]); !errors.Is(err, ErrCorruptedPayload) {
+		t.Errorf("Expected ErrCorruptedPayload for truncated metadata, got %v", err)
+	}
+}
+
+func TestMetadataFromStdin(t *testing.T) {
+	metadata := NewMetadata("-", []byte("plain text from a pipe"))
+
+	if metadata.Filename != "" {
+		t.Errorf("Expected no filename for stdin, got %q", metadata.Filename)
+	}
+	if metadata.MIMEType != "text/plain; charset=utf-8" {
+		t.Errorf("Expected sniffed text MIME type, got %q", metadata.MIMEType)
+	}
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("the stone hides a message. "), 40)
+
+	compressed, err := Compress(data)
+	if err != nil {
+		t.Fatalf("Compress failed: %v", err)
+	}
+	if len(compressed) >= len(data)/4 {
+		t.Errorf("Expected repetitive text to compress well, got %d bytes from %d", len(compressed), len(data))
+	}
+
+	result, err := Decompress(compressed)
+	if err != nil {
+		t.Fatalf("Decompress failed: %v", err)
+	}
+	if !bytes.Equal(result, data) {
+		t.Error("Decompressed data does not match the original")
+	}
+
+	if _, err := Decompress([]byte{0xFF, 0xFF, 0xFF}); !errors.Is(err, ErrCorruptedPayload) {
+		t.Errorf("Expected ErrCorruptedPayload for invalid stream, got %v", err)
+	}
+}
+
+func TestEncodeDecodeBody(t *testing.T) {
+	content := bytes.Repeat([]byte("compressible "), 50)
+	metadata := &Metadata{Filename: "notes.txt", MIMEType: "text/plain"}
+
+	body, flags, err := EncodeBody(content, metadata, true)
+	if err != nil {
+		t.Fatalf("EncodeBody failed: %v", err)
+	}
+	if flags != FlagMetadata|FlagCompressed {
+		t.Errorf("Expected metadata and compression flags, got %08b", flags)
+	}
+	if len(body) >= len(content) {
+		t.Errorf("Expected compressed body, got %d bytes from %d", len(body), len(content))
+	}
+
+	result, decoded, err := DecodeBody(body, flags)
+	if err != nil {
+		t.Fatalf("DecodeBody failed: %v", err)
+	}
+	if !bytes.Equal(result, content) || decoded == nil || *decoded != *metadata {
+		t.Errorf("Body round trip failed: metadata %+v", decoded)
+	}
+
+	random := []byte{0x8F, 0x13, 0xA7, 0x52}
+	body, flags, err = EncodeBody(random, nil, true)
+	if err != nil {
+		t.Fatalf("EncodeBody failed: %v", err)
+	}
+	if flags != 0 || !bytes.Equal(body, random) {
+		t.Errorf("Incompressible data should be stored as is, got flags %08b", flags)
+	}
+}
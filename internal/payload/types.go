@@ -0,0 +1,59 @@
+package payload
+
+import (
+	"errors"
+	"stone-analysis/internal/fec"
+)
+
+const (
+	Magic      = "STNA"
+	Version    = 5
+	HeaderSize = 16
+
+	maxFilenameSize = 1<<16 - 1
+	maxMIMETypeSize = 1<<8 - 1
+
+	maxDecompressedSize = 1 << 28
+)
+
+const (
+	FlagEncrypted uint8 = 1 << iota
+	FlagKeyedOrder
+	FlagMetadata
+	FlagCompressed
+	FlagSigned
+)
+
+var schemeCodes = map[fec.Scheme]byte{
+	fec.None:        0x00,
+	fec.Hamming:     0x1F,
+	fec.ReedSolomon: 0xE3,
+}
+
+var (
+	ErrNoPayload          = errors.New("no hidden payload found")
+	ErrCorruptedPayload   = errors.New("hidden payload is corrupted")
+	ErrUnsupportedVersion = errors.New("unsupported payload version")
+	ErrMetadataTooLarge   = errors.New("payload metadata is too large")
+)
+
+type Header struct {
+	Version  uint8
+	Method   uint8
+	Channel  uint8
+	Flags    uint8
+	FEC      fec.Scheme
+	Length   uint32
+	Checksum uint32
+}
+
+type Frame struct {
+	Header    Header
+	Data      []byte
+	Corrected int
+}
+
+type Metadata struct {
+	Filename string
+	MIMEType string
+}
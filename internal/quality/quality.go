@@ -0,0 +1,160 @@
+package quality
+
+import (
+	"fmt"
+	"math"
+	"stone-analysis/internal/dft"
+	"stone-analysis/internal/wav"
+)
+
+func ratioDB(signal, noise float64) float64 {
+	if noise == 0 {
+		return math.Inf(1)
+	}
+	return 10 * math.Log10(signal/noise)
+}
+
+func segmentalSNR(reference, test []float64) float64 {
+	var sum float64
+	segments := 0
+
+	for start := 0; start+frameSize <= len(reference); start += frameSize {
+		var signal, noise float64
+		for i := start; i < start+frameSize; i++ {
+			diff := reference[i] - test[i]
+			signal += reference[i] * reference[i]
+			noise += diff * diff
+		}
+		if signal == 0 {
+			continue
+		}
+
+		sum += math.Max(minSegmentDB, math.Min(maxSegmentDB, ratioDB(signal, noise)))
+		segments++
+	}
+
+	if segments == 0 {
+		return 0
+	}
+	return sum / float64(segments)
+}
+
+func logSpectralDistance(reference, test []float64, sampleRate float64) (float64, error) {
+	var sum float64
+	frames := 0
+
+	for start := 0; start+frameSize <= len(reference); start += frameSize {
+		referenceSpectrum, err := dft.DFT(dft.ApplyHannWindow(reference[start:start+frameSize]), sampleRate)
+		if err != nil {
+			return 0, fmt.Errorf("dft.DFT(): %w", err)
+		}
+		testSpectrum, err := dft.DFT(dft.ApplyHannWindow(test[start:start+frameSize]), sampleRate)
+		if err != nil {
+			return 0, fmt.Errorf("dft.DFT(): %w", err)
+		}
+
+		floor := math.SmallestNonzeroFloat64
+		for _, component := range referenceSpectrum.Components {
+			floor = math.Max(floor, component.Magnitude*component.Magnitude*dynamicRange)
+		}
+
+		var distance float64
+		for k, component := range referenceSpectrum.Components {
+			referencePower := math.Max(component.Magnitude*component.Magnitude, floor)
+			testPower := math.Max(testSpectrum.Components[k].Magnitude*testSpectrum.Components[k].Magnitude, floor)
+			d := 10 * math.Log10(referencePower/testPower)
+			distance += d * d
+		}
+
+		sum += math.Sqrt(distance / float64(len(referenceSpectrum.Components)))
+		frames++
+	}
+
+	if frames == 0 {
+		return 0, nil
+	}
+	return sum / float64(frames), nil
+}
+
+func Measure(reference, test []float64, sampleRate float64) (Metrics, error) {
+	if len(reference) != len(test) {
+		return Metrics{}, fmt.Errorf("%w: %d and %d samples", ErrLengthMismatch, len(reference), len(test))
+	}
+
+	var signal, noise, deviation float64
+	for i := range reference {
+		diff := reference[i] - test[i]
+		signal += reference[i] * reference[i]
+		noise += diff * diff
+		deviation = math.Max(deviation, math.Abs(diff))
+	}
+
+	lsd, err := logSpectralDistance(reference, test, sampleRate)
+	if err != nil {
+		return Metrics{}, err
+	}
+
+	metrics := Metrics{
+		SNR:                 ratioDB(signal, noise),
+		SegmentalSNR:        segmentalSNR(reference, test),
+		PSNR:                math.Inf(1),
+		MaxDeviation:        deviation,
+		LogSpectralDistance: lsd,
+	}
+	if len(reference) > 0 {
+		metrics.PSNR = ratioDB(1, noise/float64(len(reference)))
+	}
+
+	return metrics, nil
+}
+
+func concatChannels(wavFile *wav.WavFile) []float64 {
+	samples := make([]float64, 0, len(wavFile.Samples))
+	for _, channel := range wavFile.Channels() {
+		samples = append(samples, channel...)
+	}
+	return samples
+}
+
+func MeasureFiles(referenceFile, testFile string, policy wav.ValidationPolicy) (Metrics, float64, error) {
+	reference, err := wav.ReadWavFile(referenceFile, policy)
+	if err != nil {
+		return Metrics{}, 0, fmt.Errorf("wav.ReadWavFile(%s): %w", referenceFile, err)
+	}
+
+	test, err := wav.ReadWavFile(testFile, policy)
+	if err != nil {
+		return Metrics{}, 0, fmt.Errorf("wav.ReadWavFile(%s): %w", testFile, err)
+	}
+
+	if reference.FmtChunk.NumChannels != test.FmtChunk.NumChannels ||
+		reference.FmtChunk.SampleRate != test.FmtChunk.SampleRate {
+		return Metrics{}, 0, fmt.Errorf("%w: %s and %s", ErrFormatMismatch, referenceFile, testFile)
+	}
+
+	metrics, err := Measure(concatChannels(reference), concatChannels(test),
+		float64(reference.FmtChunk.SampleRate))
+	if err != nil {
+		return Metrics{}, 0, err
+	}
+
+	return metrics, wav.SampleScale(reference.FmtChunk), nil
+}
+
+func Print(metrics Metrics, fullScale float64) {
+	fmt.Printf("SNR: %.2f dB\n", metrics.SNR)
+	fmt.Printf("Segmental SNR: %.2f dB\n", metrics.SegmentalSNR)
+	fmt.Printf("PSNR: %.2f dB\n", metrics.PSNR)
+	fmt.Printf("Max deviation: %.6f (%.0f LSB)\n", metrics.MaxDeviation, math.Round(metrics.MaxDeviation*fullScale))
+	fmt.Printf("Log-spectral distance: %.4f dB\n", metrics.LogSpectralDistance)
+}
+
+func Compare(referenceFile, testFile string, policy wav.ValidationPolicy) error {
+	metrics, fullScale, err := MeasureFiles(referenceFile, testFile, policy)
+	if err != nil {
+		return err
+	}
+
+	Print(metrics, fullScale)
+	return nil
+}
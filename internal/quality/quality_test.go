@@ -0,0 +1,77 @@
+package quality
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func tone(n int) []float64 {
+	samples := make([]float64, n)
+	for i := range samples {
+		samples[i] = 0.5 * math.Sin(2*math.Pi*440*float64(i)/48000)
+	}
+	return samples
+}
+
+func TestMeasureIdentical(t *testing.T) {
+	reference := tone(4096)
+
+	metrics, err := Measure(reference, reference, 48000)
+	if err != nil {
+		t.Fatalf("Measure failed: %v", err)
+	}
+
+	if !math.IsInf(metrics.SNR, 1) || !math.IsInf(metrics.PSNR, 1) {
+		t.Errorf("Expected infinite SNR and PSNR, got %+v", metrics)
+	}
+	if metrics.SegmentalSNR != maxSegmentDB {
+		t.Errorf("Expected segmental SNR clamped to %.0f, got %.2f", maxSegmentDB, metrics.SegmentalSNR)
+	}
+	if metrics.MaxDeviation != 0 || metrics.LogSpectralDistance != 0 {
+		t.Errorf("Expected no deviation, got %+v", metrics)
+	}
+}
+
+func TestMeasureScaled(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	reference := make([]float64, 4096)
+	for i := range reference {
+		reference[i] = 0.5 * math.Sin(2*math.Pi*440*float64(i)/48000) * (1 + 0.1*rng.NormFloat64())
+	}
+	test := make([]float64, len(reference))
+	for i, sample := range reference {
+		test[i] = sample * 0.9
+	}
+
+	metrics, err := Measure(reference, test, 48000)
+	if err != nil {
+		t.Fatalf("Measure failed: %v", err)
+	}
+
+	if math.Abs(metrics.SNR-20) > 0.01 {
+		t.Errorf("Expected SNR of 20 dB, got %.4f", metrics.SNR)
+	}
+	if math.Abs(metrics.SegmentalSNR-20) > 0.01 {
+		t.Errorf("Expected segmental SNR of 20 dB, got %.4f", metrics.SegmentalSNR)
+	}
+
+	if expectedPSNR := metrics.SNR + 10*math.Log10(1/(0.25*0.5)); math.Abs(metrics.PSNR-expectedPSNR) > 0.2 {
+		t.Errorf("Expected PSNR of %.2f dB, got %.4f", expectedPSNR, metrics.PSNR)
+	}
+	if metrics.MaxDeviation < 0.04 || metrics.MaxDeviation > 0.1 {
+		t.Errorf("Expected max deviation close to 0.05, got %.6f", metrics.MaxDeviation)
+	}
+
+	expectedLSD := -20 * math.Log10(0.9)
+	if math.Abs(metrics.LogSpectralDistance-expectedLSD) > 0.01 {
+		t.Errorf("Expected LSD of %.4f dB, got %.4f", expectedLSD, metrics.LogSpectralDistance)
+	}
+}
+
+func TestMeasureLengthMismatch(t *testing.T) {
+	if _, err := Measure(tone(10), tone(11), 48000); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("Expected ErrLengthMismatch, got %v", err)
+	}
+}
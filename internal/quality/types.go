@@ -0,0 +1,23 @@
+package quality
+
+import "errors"
+
+const (
+	frameSize    = 1024
+	minSegmentDB = -10.0
+	maxSegmentDB = 35.0
+	dynamicRange = 1e-10
+)
+
+var (
+	ErrLengthMismatch = errors.New("signals have different lengths")
+	ErrFormatMismatch = errors.New("files have different formats")
+)
+
+type Metrics struct {
+	SNR                 float64
+	SegmentalSNR        float64
+	PSNR                float64
+	MaxDeviation        float64
+	LogSpectralDistance float64
+}
@@ -0,0 +1,123 @@
+package robustness
+
+import (
+	"math"
+	"math/rand"
+	"stone-analysis/internal/dft"
+)
+
+func Attacks() []Attack {
+	return []Attack{
+		{Name: "none", Apply: func(samples []float64, _ float64) []float64 { return samples }},
+		{Name: "noise", Apply: addNoise},
+		{Name: "8bit", Apply: requantize},
+		{Name: "gain", Apply: changeGain},
+		{Name: "resample", Apply: resampleRoundTrip},
+		{Name: "lowpass", Apply: lowPass},
+		{Name: "crop", Apply: crop},
+	}
+}
+
+func addNoise(samples []float64, _ float64) []float64 {
+	var energy float64
+	for _, sample := range samples {
+		energy += sample * sample
+	}
+	sigma := math.Sqrt(energy/float64(len(samples))) * math.Pow(10, -noiseSNR/20)
+
+	rng := rand.New(rand.NewSource(1))
+	noisy := make([]float64, len(samples))
+	for i, sample := range samples {
+		noisy[i] = sample + sigma*rng.NormFloat64()
+	}
+	return noisy
+}
+
+func requantize(samples []float64, _ float64) []float64 {
+	fullScale := float64(int(1) << (requantizeBits - 1))
+	quantized := make([]float64, len(samples))
+	for i, sample := range samples {
+		quantized[i] = math.Round(sample*fullScale) / fullScale
+	}
+	return quantized
+}
+
+func changeGain(samples []float64, _ float64) []float64 {
+	scaled := make([]float64, len(samples))
+	for i, sample := range samples {
+		scaled[i] = sample * gainFactor
+	}
+	return scaled
+}
+
+func lowPassKernel(cutoff float64, taps int) []float64 {
+	kernel := make([]float64, taps)
+	center := float64(taps-1) / 2
+	for i := range kernel {
+		x := float64(i) - center
+		if x == 0 {
+			kernel[i] = 2 * cutoff
+		} else {
+			kernel[i] = math.Sin(2*math.Pi*cutoff*x) / (math.Pi * x)
+		}
+	}
+	return dft.ApplyHammingWindow(kernel)
+}
+
+func lowPass(samples []float64, sampleRate float64) []float64 {
+	kernel := lowPassKernel(lowPassCutoff/sampleRate, filterTaps)
+	half := len(kernel) / 2
+
+	filtered := make([]float64, len(samples))
+	for i := range filtered {
+		var sum float64
+		for k, weight := range kernel {
+			if j := i + k - half; j >= 0 && j < len(samples) {
+				sum += weight * samples[j]
+			}
+		}
+		filtered[i] = sum
+	}
+	return filtered
+}
+
+func resample(samples []float64, from, to float64) []float64 {
+	ratio := to / from
+	cutoff := 0.5 * math.Min(1, ratio)
+	resampled := make([]float64, int(float64(len(samples))*ratio))
+
+	for i := range resampled {
+		position := float64(i) / ratio
+		center := int(math.Floor(position))
+
+		var sum float64
+		for j := center - resampleTaps + 1; j <= center+resampleTaps; j++ {
+			if j < 0 || j >= len(samples) {
+				continue
+			}
+			x := position - float64(j)
+			window := 0.54 + 0.46*math.Cos(math.Pi*x/resampleTaps)
+			weight := 2 * cutoff
+			if x != 0 {
+				weight = math.Sin(2*math.Pi*cutoff*x) / (math.Pi * x)
+			}
+			sum += samples[j] * weight * window
+		}
+		resampled[i] = sum
+	}
+
+	return resampled
+}
+
+func resampleRoundTrip(samples []float64, sampleRate float64) []float64 {
+	restored := resample(resample(samples, sampleRate, resampleRate), resampleRate, sampleRate)
+	if len(restored) < len(samples) {
+		restored = append(restored, make([]float64, len(samples)-len(restored))...)
+	}
+	return restored[:len(samples)]
+}
+
+func crop(samples []float64, _ float64) []float64 {
+	trim := int(float64(len(samples)) * cropFraction / 2)
+	return samples[trim : len(samples)-trim]
+}
@@ -0,0 +1,136 @@
+package robustness
+
+import (
+	"fmt"
+	"math"
+	"stone-analysis/internal/stego"
+	"stone-analysis/internal/wav"
+)
+
+func testBits(count int) []uint8 {
+	prng := stego.NewPRNG("robustness", "payload")
+	bits := make([]uint8, count)
+	for i := range bits {
+		bits[i] = uint8(prng.Intn(2))
+	}
+	return bits
+}
+
+func writeThrough(samples []float64, fullScale float64) []float64 {
+	quantized := make([]float64, len(samples))
+	for i, sample := range samples {
+		value := math.Max(-fullScale, math.Min(fullScale-1, math.Round(sample*fullScale)))
+		quantized[i] = value / fullScale
+	}
+	return quantized
+}
+
+func bitErrorRate(method stego.Method, carrier *stego.Carrier, expected []uint8) float64 {
+	count := min(len(expected), method.Capacity(carrier))
+
+	bits, err := method.Extract(carrier, count)
+	if err != nil {
+		return 1
+	}
+
+	bitErrors := len(expected) - count
+	for i, bit := range bits {
+		if bit != expected[i] {
+			bitErrors++
+		}
+	}
+	return float64(bitErrors) / float64(len(expected))
+}
+
+func Evaluate(carrier *stego.Carrier, layout stego.Layout, methods []stego.Method, attacks []Attack) ([]Result, error) {
+	var results []Result
+
+	for _, method := range methods {
+		stegoCarrier := &stego.Carrier{
+			Samples:    layout.Gather(carrier.Samples, stego.BlockSize(method)),
+			SampleRate: carrier.SampleRate,
+			FullScale:  carrier.FullScale,
+		}
+
+		count := min(method.Capacity(stegoCarrier), maxPayloadBits)
+		if count == 0 {
+			continue
+		}
+
+		expected := testBits(count)
+		if err := method.Embed(stegoCarrier, expected); err != nil {
+			return nil, fmt.Errorf("%s.Embed(): %w", method.Name(), err)
+		}
+		stegoSamples := writeThrough(stegoCarrier.Samples, carrier.FullScale)
+
+		result := Result{Method: method.Name(), Bits: count, BER: make([]float64, len(attacks))}
+		for i, attack := range attacks {
+			attacked := &stego.Carrier{
+				Samples:    writeThrough(attack.Apply(stegoSamples, carrier.SampleRate), carrier.FullScale),
+				SampleRate: carrier.SampleRate,
+				FullScale:  carrier.FullScale,
+			}
+			result.BER[i] = bitErrorRate(method, attacked, expected)
+		}
+
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		return nil, ErrNoMethodFits
+	}
+
+	return results, nil
+}
+
+func Run(inFile string, opts Options) error {
+	config := stego.Config{Key: opts.StegoKey, Strength: opts.Strength}
+
+	methods := stego.All(config)
+	if opts.Method != "" {
+		method, err := stego.New(opts.Method, config)
+		if err != nil {
+			return fmt.Errorf("stego.New(%s): %w", opts.Method, err)
+		}
+		methods = []stego.Method{method}
+	}
+
+	wavFile, err := wav.ReadWavFile(inFile, opts.RatePolicy)
+	if err != nil {
+		return fmt.Errorf("wav.ReadWavFile(%s): %w", inFile, err)
+	}
+
+	layout, err := stego.NewLayout(int(wavFile.FmtChunk.NumChannels), 0)
+	if err != nil {
+		return fmt.Errorf("stego.NewLayout(): %w", err)
+	}
+
+	carrier := &stego.Carrier{
+		Samples:    wavFile.Samples,
+		SampleRate: float64(wavFile.FmtChunk.SampleRate),
+		FullScale:  wav.SampleScale(wavFile.FmtChunk),
+	}
+
+	attacks := Attacks()
+	results, err := Evaluate(carrier, layout, methods, attacks)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Bit error rate after each attack (%d-bit payload at most)\n", maxPayloadBits)
+	fmt.Printf("%-10s %6s", "METHOD", "BITS")
+	for _, attack := range attacks {
+		fmt.Printf(" %9s", attack.Name)
+	}
+	fmt.Println()
+
+	for _, result := range results {
+		fmt.Printf("%-10s %6d", result.Method, result.Bits)
+		for _, ber := range result.BER {
+			fmt.Printf(" %8.2f%%", ber*100)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package robustness
+
+import (
+	"math"
+	"stone-analysis/internal/dft"
+	"stone-analysis/internal/stego"
+	"testing"
+)
+
+func sine(frequency float64, n int) []float64 {
+	samples := make([]float64, n)
+	for i := range samples {
+		samples[i] = 0.5 * math.Sin(2*math.Pi*frequency*float64(i)/48000)
+	}
+	return samples
+}
+
+func TestLowPass(t *testing.T) {
+	pass := lowPass(sine(1000, 4800), 48000)
+	if level := dft.Goertzel(pass[1000:3400], 1000, 48000); math.Abs(level-0.5) > 0.02 {
+		t.Errorf("Expected 1 kHz to pass at 0.5, got %.4f", level)
+	}
+
+	stop := lowPass(sine(15000, 4800), 48000)
+	if level := dft.Goertzel(stop[1000:3400], 15000, 48000); level > 0.01 {
+		t.Errorf("Expected 15 kHz to be attenuated, got %.4f", level)
+	}
+}
+
+func TestResampleRoundTrip(t *testing.T) {
+	samples := sine(1000, 4800)
+	restored := resampleRoundTrip(samples, 48000)
+
+	if len(restored) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(restored))
+	}
+
+	for i := 200; i < len(samples)-200; i++ {
+		if math.Abs(restored[i]-samples[i]) > 0.01 {
+			t.Fatalf("Sample %d: expected %.4f, got %.4f", i, samples[i], restored[i])
+		}
+	}
+}
+
+func TestAttacksShapes(t *testing.T) {
+	samples := sine(440, 1000)
+
+	for _, attack := range Attacks() {
+		attacked := attack.Apply(samples, 48000)
+		expected := len(samples)
+		if attack.Name == "crop" {
+			expected = 900
+		}
+		if len(attacked) != expected {
+			t.Errorf("%s: expected %d samples, got %d", attack.Name, expected, len(attacked))
+		}
+	}
+
+	ramp := make([]float64, 1000)
+	for i := range ramp {
+		ramp[i] = float64(i)
+	}
+	if offset := int(crop(ramp, 48000)[0]); offset == 0 {
+		t.Error("Expected crop to move the payload away from sample 0")
+	}
+
+	if requantized := requantize([]float64{0.3}, 48000); requantized[0] != 38.0/128 {
+		t.Errorf("Expected 8-bit step, got %v", requantized[0])
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	carrier := &stego.Carrier{Samples: sine(440, 8192), SampleRate: 48000, FullScale: 32768}
+	attacks := []Attack{Attacks()[0], {Name: "gain", Apply: changeGain}, {Name: "crop", Apply: crop}}
+
+	results, err := Evaluate(carrier, stego.Layout{Channels: 1}, []stego.Method{stego.NewLSB("")}, attacks)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	result := results[0]
+	if result.Bits != maxPayloadBits {
+		t.Errorf("Expected %d bits, got %d", maxPayloadBits, result.Bits)
+	}
+	if result.BER[0] != 0 {
+		t.Errorf("Expected no errors without attack, got %.4f", result.BER[0])
+	}
+	if result.BER[1] < 0.3 {
+		t.Errorf("Expected LSB to break under gain, got %.4f", result.BER[1])
+	}
+	if result.BER[2] < 0.3 {
+		t.Errorf("Expected sequential LSB to lose sync under crop, got %.4f", result.BER[2])
+	}
+
+	if _, err := Evaluate(&stego.Carrier{SampleRate: 48000, FullScale: 32768}, stego.Layout{Channels: 1}, []stego.Method{stego.NewLSB("")}, attacks); err != ErrNoMethodFits {
+		t.Errorf("Expected ErrNoMethodFits, got %v", err)
+	}
+}
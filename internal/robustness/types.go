@@ -0,0 +1,39 @@
+package robustness
+
+import (
+	"errors"
+	"stone-analysis/internal/wav"
+)
+
+const (
+	maxPayloadBits = 2048
+
+	noiseSNR       = 40.0
+	requantizeBits = 8
+	gainFactor     = 0.8
+	resampleRate   = 44100.0
+	lowPassCutoff  = 8000.0
+	filterTaps     = 127
+	resampleTaps   = 32
+	cropFraction   = 0.1
+)
+
+var ErrNoMethodFits = errors.New("carrier is too short for any embedding method")
+
+type Attack struct {
+	Name  string
+	Apply func(samples []float64, sampleRate float64) []float64
+}
+
+type Options struct {
+	Method     string
+	StegoKey   string
+	Strength   float64
+	RatePolicy wav.ValidationPolicy
+}
+
+type Result struct {
+	Method string
+	Bits   int
+	BER    []float64
+}
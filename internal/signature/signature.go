@@ -0,0 +1,83 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+func readPEM(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile(%s): %w", path, err)
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoPEMBlock, path)
+	}
+
+	return block.Bytes, nil
+}
+
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	der, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("x509.ParsePKCS8PrivateKey(): %w", err)
+	}
+
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotEd25519Key, path)
+	}
+
+	return privateKey, nil
+}
+
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	der, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("x509.ParsePKIXPublicKey(): %w", err)
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotEd25519Key, path)
+	}
+
+	return publicKey, nil
+}
+
+func Sign(message []byte, privateKey ed25519.PrivateKey) []byte {
+	signed := make([]byte, 0, len(message)+Size)
+	signed = append(signed, message...)
+	return append(signed, ed25519.Sign(privateKey, message)...)
+}
+
+func Split(signed []byte) ([]byte, []byte, error) {
+	if len(signed) < Size {
+		return nil, nil, fmt.Errorf("%w: %d bytes is shorter than a signature", ErrInvalidSignature, len(signed))
+	}
+
+	split := len(signed) - Size
+	return signed[:split], signed[split:], nil
+}
+
+func Verify(message, sig []byte, publicKey ed25519.PublicKey) error {
+	if !ed25519.Verify(publicKey, message, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
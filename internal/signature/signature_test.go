@@ -0,0 +1,104 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeKeys(t *testing.T, dir string) (string, string) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey failed: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey failed: %v", err)
+	}
+
+	privatePath := filepath.Join(dir, "priv.pem")
+	publicPath := filepath.Join(dir, "pub.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	return privatePath, publicPath
+}
+
+func TestSignVerify(t *testing.T) {
+	dir := t.TempDir()
+	privatePath, publicPath := writeKeys(t, dir)
+
+	privateKey, err := LoadPrivateKey(privatePath)
+	if err != nil {
+		t.Fatalf("LoadPrivateKey failed: %v", err)
+	}
+	publicKey, err := LoadPublicKey(publicPath)
+	if err != nil {
+		t.Fatalf("LoadPublicKey failed: %v", err)
+	}
+
+	signed := Sign([]byte("provenance"), privateKey)
+	if len(signed) != len("provenance")+Size {
+		t.Fatalf("Expected %d bytes, got %d", len("provenance")+Size, len(signed))
+	}
+
+	message, sig, err := Split(signed)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if string(message) != "provenance" {
+		t.Errorf("Expected 'provenance', got %q", message)
+	}
+	if err := Verify(message, sig, publicKey); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+
+	signed[0] ^= 0x01
+	message, sig, _ = Split(signed)
+	if err := Verify(message, sig, publicKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+
+	_, otherPublic := writeKeys(t, t.TempDir())
+	otherKey, _ := LoadPublicKey(otherPublic)
+	signed[0] ^= 0x01
+	message, sig, _ = Split(signed)
+	if err := Verify(message, sig, otherKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature with another key, got %v", err)
+	}
+}
+
+func TestLoadKeyErrors(t *testing.T) {
+	dir := t.TempDir()
+	privatePath, publicPath := writeKeys(t, dir)
+
+	if _, err := LoadPrivateKey(publicPath); err == nil {
+		t.Error("Expected an error when loading a public key as private")
+	}
+
+	if _, err := LoadPublicKey(privatePath); err == nil {
+		t.Error("Expected an error when loading a private key as public")
+	}
+
+	garbage := filepath.Join(dir, "garbage.pem")
+	if err := os.WriteFile(garbage, []byte("not a key"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := LoadPublicKey(garbage); !errors.Is(err, ErrNoPEMBlock) {
+		t.Errorf("Expected ErrNoPEMBlock, got %v", err)
+	}
+}
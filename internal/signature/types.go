@@ -0,0 +1,15 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"errors"
+)
+
+const Size = ed25519.SignatureSize
+
+var (
+	ErrNoPEMBlock       = errors.New("no PEM block found in key file")
+	ErrNotEd25519Key    = errors.New("key is not an Ed25519 key")
+	ErrUnsignedPayload  = errors.New("payload is not signed")
+	ErrInvalidSignature = errors.New("signature does not match, the message is not authentic")
+)
@@ -0,0 +1,164 @@
+package stego
+
+import (
+	"fmt"
+	"math"
+	"stone-analysis/internal/dft"
+)
+
+const (
+	adaptiveFrameSize   = 1024
+	adaptiveMaxBits     = 3
+	adaptiveSilenceDB   = -60.0
+	adaptiveMinFlatness = 1e-4
+)
+
+type AdaptiveLSB struct{}
+
+func NewAdaptiveLSB() *AdaptiveLSB {
+	return &AdaptiveLSB{}
+}
+
+func (m *AdaptiveLSB) ID() uint8 {
+	return MethodAdaptive
+}
+
+func (m *AdaptiveLSB) Name() string {
+	return "adaptive"
+}
+
+func maskedValue(sample, fullScale float64) float64 {
+	value := int(math.Round(sample * fullScale))
+	return float64(value&^(1<<adaptiveMaxBits-1)) / fullScale
+}
+
+func spectralFlatness(result *dft.DFTResult) float64 {
+	var logSum, sum float64
+	count := 0
+
+	for _, component := range result.Components[1:] {
+		power := component.Magnitude*component.Magnitude + 1e-20
+		logSum += math.Log(power)
+		sum += power
+		count++
+	}
+
+	if count == 0 || sum == 0 {
+		return 0
+	}
+	return math.Exp(logSum/float64(count)) / (sum / float64(count))
+}
+
+func bitsForLevel(levelDB float64) int {
+	switch {
+	case levelDB < adaptiveSilenceDB:
+		return 0
+	case levelDB < -40:
+		return 1
+	case levelDB < -20:
+		return 2
+	}
+	return adaptiveMaxBits
+}
+
+func regionMap(carrier *Carrier) ([]int, error) {
+	regions := make([]int, len(carrier.Samples)/adaptiveFrameSize)
+	masked := make([]float64, adaptiveFrameSize)
+
+	for f := range regions {
+		frame := carrier.Samples[f*adaptiveFrameSize : (f+1)*adaptiveFrameSize]
+
+		var energy float64
+		for i, sample := range frame {
+			masked[i] = maskedValue(sample, carrier.FullScale)
+			energy += masked[i] * masked[i]
+		}
+
+		rms := math.Sqrt(energy / adaptiveFrameSize)
+		if rms == 0 {
+			continue
+		}
+
+		spectrum, err := dft.AnalyzeFrequencies(masked, carrier.SampleRate, true)
+		if err != nil {
+			return nil, fmt.Errorf("dft.AnalyzeFrequencies(): %w", err)
+		}
+
+		if spectralFlatness(spectrum) < adaptiveMinFlatness {
+			continue
+		}
+
+		regions[f] = bitsForLevel(20 * math.Log10(rms))
+	}
+
+	return regions, nil
+}
+
+func regionCapacity(regions []int) int {
+	total := 0
+	for _, bits := range regions {
+		total += bits * adaptiveFrameSize
+	}
+	return total
+}
+
+func (m *AdaptiveLSB) Capacity(carrier *Carrier) int {
+	regions, err := regionMap(carrier)
+	if err != nil {
+		return 0
+	}
+	return regionCapacity(regions)
+}
+
+func (m *AdaptiveLSB) Embed(carrier *Carrier, bits []uint8) error {
+	regions, err := regionMap(carrier)
+	if err != nil {
+		return fmt.Errorf("regionMap(): %w", err)
+	}
+	if len(bits) > regionCapacity(regions) {
+		return ErrPayloadTooLarge
+	}
+
+	position := 0
+	for f, k := range regions {
+		for i := f * adaptiveFrameSize; i < (f+1)*adaptiveFrameSize && k > 0; i++ {
+			if position >= len(bits) {
+				return nil
+			}
+
+			value := int(math.Round(carrier.Samples[i] * carrier.FullScale))
+			for b := k - 1; b >= 0 && position < len(bits); b-- {
+				value = value&^(1<<b) | int(bits[position]&1)<<b
+				position++
+			}
+			carrier.Samples[i] = float64(value) / carrier.FullScale
+		}
+	}
+
+	return nil
+}
+
+func (m *AdaptiveLSB) Extract(carrier *Carrier, count int) ([]uint8, error) {
+	regions, err := regionMap(carrier)
+	if err != nil {
+		return nil, fmt.Errorf("regionMap(): %w", err)
+	}
+	if count > regionCapacity(regions) {
+		return nil, ErrNotEnoughBits
+	}
+
+	bits := make([]uint8, 0, count)
+	for f, k := range regions {
+		for i := f * adaptiveFrameSize; i < (f+1)*adaptiveFrameSize && k > 0; i++ {
+			value := int(math.Round(carrier.Samples[i] * carrier.FullScale))
+			for b := k - 1; b >= 0; b-- {
+				if len(bits) == count {
+					return bits, nil
+				}
+				bits = append(bits, uint8(value>>b&1))
+			}
+		}
+	}
+
+	return bits, nil
+}
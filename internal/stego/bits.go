@@ -0,0 +1,27 @@
+package stego
+
+func BytesToBits(data []byte) []uint8 {
+	bits := make([]uint8, len(data)*8)
+
+	for i, b := range data {
+		for j := 0; j < 8; j++ {
+			bits[i*8+j] = (b >> (7 - j)) & 1
+		}
+	}
+
+	return bits
+}
+
+func BitsToBytes(bits []uint8) []byte {
+	data := make([]byte, len(bits)/8)
+
+	for i := range data {
+		var b byte
+		for j := 0; j < 8; j++ {
+			b = (b << 1) | (bits[i*8+j] & 1)
+		}
+		data[i] = b
+	}
+
+	return data
+}
@@ -0,0 +1,66 @@
+package stego
+
+import (
+	"fmt"
+	"stone-analysis/internal/wav"
+)
+
+type Layout struct {
+	Channels int
+	Channel  int
+}
+
+func NewLayout(channels, channel int) (Layout, error) {
+	if channels < 1 || channel < 0 || channel > channels {
+		return Layout{}, fmt.Errorf("%w: %d of %d", ErrInvalidChannel, channel, channels)
+	}
+	return Layout{Channels: channels, Channel: channel}, nil
+}
+
+func Layouts(channels int) []Layout {
+	layouts := []Layout{{Channels: channels}}
+	if channels > 1 {
+		for channel := 1; channel <= channels; channel++ {
+			layouts = append(layouts, Layout{Channels: channels, Channel: channel})
+		}
+	}
+	return layouts
+}
+
+func (l Layout) String() string {
+	if l.Channel == 0 {
+		return fmt.Sprintf("all %d channels", l.Channels)
+	}
+	return fmt.Sprintf("channel %d of %d", l.Channel, l.Channels)
+}
+
+func (l Layout) Gather(samples []float64, block int) []float64 {
+	channels := wav.Deinterleave(samples, l.Channels)
+	if l.Channel != 0 {
+		return channels[l.Channel-1]
+	}
+
+	frames := len(samples) / l.Channels / block * block
+	gathered := make([]float64, 0, frames*l.Channels)
+	for start := 0; start < frames; start += block {
+		for _, channel := range channels {
+			gathered = append(gathered, channel[start:start+block]...)
+		}
+	}
+	return gathered
+}
+
+func (l Layout) Scatter(samples, gathered []float64, block int) {
+	channels := wav.Deinterleave(samples, l.Channels)
+	if l.Channel != 0 {
+		channels[l.Channel-1] = gathered
+	} else {
+		position := 0
+		for start := 0; position < len(gathered); start += block {
+			for _, channel := range channels {
+				position += copy(channel[start:start+block], gathered[position:])
+			}
+		}
+	}
+	copy(samples, wav.Interleave(channels))
+}
@@ -0,0 +1,92 @@
+package stego
+
+const (
+	dsssChipsPerBit = 1024
+	DefaultStrength = 0.003
+)
+
+type SpreadSpectrum struct {
+	key      string
+	strength float64
+}
+
+func NewSpreadSpectrum(key string, strength float64) *SpreadSpectrum {
+	if strength <= 0 {
+		strength = DefaultStrength
+	}
+	return &SpreadSpectrum{key: key, strength: strength}
+}
+
+func (m *SpreadSpectrum) ID() uint8 {
+	return MethodSpreadSpectrum
+}
+
+func (m *SpreadSpectrum) Name() string {
+	return "dsss"
+}
+
+func (m *SpreadSpectrum) Capacity(carrier *Carrier) int {
+	return len(carrier.Samples) / dsssChipsPerBit
+}
+
+func (m *SpreadSpectrum) chips(prng *PRNG) []float64 {
+	chips := make([]float64, dsssChipsPerBit)
+	for i := 0; i < dsssChipsPerBit; i += 64 {
+		value := prng.Uint64()
+		for j := 0; j < 64; j++ {
+			chips[i+j] = float64(int(value>>j&1)*2 - 1)
+		}
+	}
+	return chips
+}
+
+func correlate(segment, chips []float64) float64 {
+	var sum float64
+	for i, chip := range chips {
+		sum += segment[i] * chip
+	}
+	return sum / float64(len(chips))
+}
+
+func (m *SpreadSpectrum) Embed(carrier *Carrier, bits []uint8) error {
+	if len(bits) > m.Capacity(carrier) {
+		return ErrPayloadTooLarge
+	}
+
+	prng := NewPRNG(m.key, "dsss")
+
+	for i, bit := range bits {
+		segment := carrier.Samples[i*dsssChipsPerBit : (i+1)*dsssChipsPerBit]
+		chips := m.chips(prng)
+
+		symbol := 1.0
+		if bit == 1 {
+			symbol = -1.0
+		}
+
+		delta := m.strength*symbol - correlate(segment, chips)
+		for j, chip := range chips {
+			segment[j] += delta * chip
+		}
+	}
+
+	return nil
+}
+
+func (m *SpreadSpectrum) Extract(carrier *Carrier, count int) ([]uint8, error) {
+	if count > m.Capacity(carrier) {
+		return nil, ErrNotEnoughBits
+	}
+
+	prng := NewPRNG(m.key, "dsss")
+	bits := make([]uint8, count)
+
+	for i := range bits {
+		segment := carrier.Samples[i*dsssChipsPerBit : (i+1)*dsssChipsPerBit]
+		if correlate(segment, m.chips(prng)) < 0 {
+			bits[i] = 1
+		}
+	}
+
+	return bits, nil
+}
@@ -0,0 +1,187 @@
+package stego
+
+import (
+	"fmt"
+	"math"
+	"stone-analysis/internal/dft"
+)
+
+const (
+	echoSegmentSize = 2048
+	echoDelayZero   = 60
+	echoDelayOne    = 90
+	echoAmplitude   = 0.4
+	echoRampSize    = 256
+)
+
+type EchoHiding struct{}
+
+func NewEchoHiding() *EchoHiding {
+	return &EchoHiding{}
+}
+
+func (m *EchoHiding) ID() uint8 {
+	return MethodEcho
+}
+
+func (m *EchoHiding) Name() string {
+	return "echo"
+}
+
+func (m *EchoHiding) BlockSize() int {
+	return echoSegmentSize
+}
+
+func (m *EchoHiding) Capacity(carrier *Carrier) int {
+	return len(carrier.Samples) / echoSegmentSize
+}
+
+func echoMixer(bits []uint8, length int) []float64 {
+	mixer := make([]float64, length)
+
+	for i, bit := range bits {
+		for j := 0; j < echoSegmentSize; j++ {
+			mixer[i*echoSegmentSize+j] = float64(bit)
+		}
+	}
+
+	smoothed := make([]float64, length)
+	var sum float64
+	for i := 0; i < length; i++ {
+		sum += mixer[i]
+		if i >= echoRampSize {
+			sum -= mixer[i-echoRampSize]
+		}
+		smoothed[i] = sum / echoRampSize
+	}
+
+	shift := echoRampSize / 2
+	copy(smoothed, smoothed[shift:])
+	for i := length - shift; i < length; i++ {
+		smoothed[i] = mixer[i]
+	}
+
+	return smoothed
+}
+
+func echoGain(samples []float64, limit float64) []float64 {
+	numSegments := (len(samples) + echoSegmentSize - 1) / echoSegmentSize
+	segmentGains := make([]float64, numSegments)
+	clipped := false
+
+	for i := range segmentGains {
+		var peak float64
+		for _, sample := range samples[i*echoSegmentSize : min((i+1)*echoSegmentSize, len(samples))] {
+			peak = math.Max(peak, math.Abs(sample))
+		}
+		segmentGains[i] = 1
+		if peak > limit {
+			segmentGains[i] = limit / peak
+			clipped = true
+		}
+	}
+	if !clipped {
+		return nil
+	}
+
+	half := echoRampSize / 2
+	sums := make([]float64, len(samples)+1)
+	for n := range samples {
+		first := segmentGains[max(n-half, 0)/echoSegmentSize]
+		last := segmentGains[min(n+half, len(samples)-1)/echoSegmentSize]
+		sums[n+1] = sums[n] + math.Min(first, last)
+	}
+
+	gains := make([]float64, len(samples))
+	for n := range gains {
+		start, end := max(n-half, 0), min(n+half+1, len(samples))
+		gains[n] = (sums[end] - sums[start]) / float64(end-start)
+	}
+
+	return gains
+}
+
+func (m *EchoHiding) Embed(carrier *Carrier, bits []uint8) error {
+	if len(bits) > m.Capacity(carrier) {
+		return ErrPayloadTooLarge
+	}
+
+	original := make([]float64, len(carrier.Samples))
+	copy(original, carrier.Samples)
+
+	length := len(bits) * echoSegmentSize
+	mixer := echoMixer(bits, length)
+
+	for n := 0; n < length; n++ {
+		var echoZero, echoOne float64
+		if n >= echoDelayZero {
+			echoZero = original[n-echoDelayZero]
+		}
+		if n >= echoDelayOne {
+			echoOne = original[n-echoDelayOne]
+		}
+
+		echo := (1-mixer[n])*echoZero + mixer[n]*echoOne
+		carrier.Samples[n] = original[n] + echoAmplitude*echo
+	}
+
+	gains := echoGain(carrier.Samples, 1-1/carrier.FullScale)
+	for n, gain := range gains {
+		carrier.Samples[n] *= gain
+	}
+
+	return nil
+}
+
+func cepstrum(segment []float64) ([]float64, error) {
+	windowed := dft.ApplyHannWindow(segment)
+
+	input := make([]dft.Complex, len(windowed))
+	for i, sample := range windowed {
+		input[i] = dft.Complex{Real: sample}
+	}
+
+	spectrum, err := dft.FFT(input)
+	if err != nil {
+		return nil, fmt.Errorf("dft.FFT(): %w", err)
+	}
+
+	for i, value := range spectrum {
+		spectrum[i] = dft.Complex{Real: math.Log(value.Magnitude() + 1e-12)}
+	}
+
+	quefrency, err := dft.IFFT(spectrum)
+	if err != nil {
+		return nil, fmt.Errorf("dft.IFFT(): %w", err)
+	}
+
+	result := make([]float64, len(quefrency))
+	for i, value := range quefrency {
+		result[i] = value.Real
+	}
+
+	return result, nil
+}
+
+func (m *EchoHiding) Extract(carrier *Carrier, count int) ([]uint8, error) {
+	if count > m.Capacity(carrier) {
+		return nil, ErrNotEnoughBits
+	}
+
+	bits := make([]uint8, count)
+
+	for i := range bits {
+		segment := carrier.Samples[i*echoSegmentSize : (i+1)*echoSegmentSize]
+
+		ceps, err := cepstrum(segment)
+		if err != nil {
+			return nil, err
+		}
+
+		if ceps[echoDelayOne] > ceps[echoDelayZero] {
+			bits[i] = 1
+		}
+	}
+
+	return bits, nil
+}
@@ -0,0 +1,81 @@
+package stego
+
+import "math"
+
+func slot(order []int, i int) int {
+	if order == nil {
+		return i
+	}
+	return order[i]
+}
+
+func EmbedLSB(samples []float64, fullScale float64, bits []uint8, order []int) error {
+	if len(bits) > len(samples) {
+		return ErrPayloadTooLarge
+	}
+
+	for i, bit := range bits {
+		index := slot(order, i)
+		value := int(math.Round(samples[index] * fullScale))
+		value = (value &^ 1) | int(bit&1)
+		samples[index] = float64(value) / fullScale
+	}
+
+	return nil
+}
+
+func ExtractLSB(samples []float64, fullScale float64, count int, order []int) ([]uint8, error) {
+	if count > len(samples) {
+		return nil, ErrNotEnoughBits
+	}
+
+	bits := make([]uint8, count)
+
+	for i := 0; i < count; i++ {
+		value := int(math.Round(samples[slot(order, i)] * fullScale))
+		bits[i] = uint8(value & 1)
+	}
+
+	return bits, nil
+}
+
+type LSB struct {
+	key   string
+	order []int
+}
+
+func NewLSB(key string) *LSB {
+	return &LSB{key: key}
+}
+
+func (m *LSB) ID() uint8 {
+	return MethodLSB
+}
+
+func (m *LSB) Name() string {
+	return "lsb"
+}
+
+func (m *LSB) orderFor(n int) []int {
+	if m.key == "" {
+		return nil
+	}
+	if len(m.order) != n {
+		m.order = Permutation(n, m.key)
+	}
+	return m.order
+}
+
+func (m *LSB) Capacity(carrier *Carrier) int {
+	return len(carrier.Samples)
+}
+
+func (m *LSB) Embed(carrier *Carrier, bits []uint8) error {
+	order := m.orderFor(len(carrier.Samples))
+	return EmbedLSB(carrier.Samples, carrier.FullScale, bits, order)
+}
+
+func (m *LSB) Extract(carrier *Carrier, count int) ([]uint8, error) {
+	order := m.orderFor(len(carrier.Samples))
+	return ExtractLSB(carrier.Samples, carrier.FullScale, count, order)
+}
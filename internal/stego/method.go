@@ -0,0 +1,39 @@
+package stego
+
+import "fmt"
+
+func All(config Config) []Method {
+	return []Method{
+		NewLSB(config.Key),
+		NewPhaseCoding(),
+		NewSpreadSpectrum(config.Key, config.Strength),
+		NewEchoHiding(),
+		NewSpectralCoefficients(),
+		NewAdaptiveLSB(),
+	}
+}
+
+func New(name string, config Config) (Method, error) {
+	for _, method := range All(config) {
+		if method.Name() == name {
+			return method, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownMethod, name)
+}
+
+func BlockSize(method Method) int {
+	if blocked, ok := method.(BlockMethod); ok {
+		return blocked.BlockSize()
+	}
+	return 1
+}
+
+func Names() []string {
+	methods := All(Config{})
+	names := make([]string, len(methods))
+	for i, method := range methods {
+		names[i] = method.Name()
+	}
+	return names
+}
@@ -0,0 +1,112 @@
+package stego
+
+import (
+	"fmt"
+	"math"
+	"stone-analysis/internal/dft"
+)
+
+const (
+	phaseSegmentSize  = 8192
+	phaseFirstBin     = phaseSegmentSize / 32
+	phaseBinCount     = phaseSegmentSize / 8
+	phaseMinMagnitude = phaseSegmentSize * 1e-4
+)
+
+type PhaseCoding struct{}
+
+func NewPhaseCoding() *PhaseCoding {
+	return &PhaseCoding{}
+}
+
+func (m *PhaseCoding) ID() uint8 {
+	return MethodPhase
+}
+
+func (m *PhaseCoding) Name() string {
+	return "phase"
+}
+
+func (m *PhaseCoding) BlockSize() int {
+	return phaseSegmentSize
+}
+
+func (m *PhaseCoding) Capacity(carrier *Carrier) int {
+	if len(carrier.Samples) < phaseSegmentSize {
+		return 0
+	}
+	return phaseBinCount
+}
+
+func (m *PhaseCoding) Embed(carrier *Carrier, bits []uint8) error {
+	if len(bits) > m.Capacity(carrier) {
+		return ErrPayloadTooLarge
+	}
+
+	numSegments := len(carrier.Samples) / phaseSegmentSize
+	spectra := make([]*dft.DFTResult, numSegments)
+
+	for k := range spectra {
+		segment := carrier.Samples[k*phaseSegmentSize : (k+1)*phaseSegmentSize]
+		spectrum, err := dft.DFT(segment, carrier.SampleRate)
+		if err != nil {
+			return fmt.Errorf("dft.DFT(): %w", err)
+		}
+		spectra[k] = spectrum
+	}
+
+	previousPhases := make([]float64, len(bits))
+	newPhases := make([]float64, len(bits))
+
+	for k, spectrum := range spectra {
+		for i := range bits {
+			component := &spectrum.Components[phaseFirstBin+i]
+			value := dft.Complex{Real: component.Real, Imag: component.Imag}
+			magnitude := value.Magnitude()
+			phase := value.Phase()
+
+			if k == 0 {
+				newPhases[i] = math.Pi / 2
+				if bits[i] == 1 {
+					newPhases[i] = -math.Pi / 2
+				}
+				magnitude = math.Max(magnitude, phaseMinMagnitude)
+			} else {
+				newPhases[i] += phase - previousPhases[i]
+			}
+			previousPhases[i] = phase
+
+			component.Real = magnitude * math.Cos(newPhases[i])
+			component.Imag = magnitude * math.Sin(newPhases[i])
+			component.Phase = math.Atan2(component.Imag, component.Real)
+		}
+
+		segment, err := dft.IDFT(spectrum)
+		if err != nil {
+			return fmt.Errorf("dft.IDFT(): %w", err)
+		}
+		copy(carrier.Samples[k*phaseSegmentSize:], segment)
+	}
+
+	return nil
+}
+
+func (m *PhaseCoding) Extract(carrier *Carrier, count int) ([]uint8, error) {
+	if count > m.Capacity(carrier) {
+		return nil, ErrNotEnoughBits
+	}
+
+	spectrum, err := dft.DFT(carrier.Samples[:phaseSegmentSize], carrier.SampleRate)
+	if err != nil {
+		return nil, fmt.Errorf("dft.DFT(): %w", err)
+	}
+
+	bits := make([]uint8, count)
+	for i := range bits {
+		if spectrum.Components[phaseFirstBin+i].Phase < 0 {
+			bits[i] = 1
+		}
+	}
+
+	return bits, nil
+}
@@ -0,0 +1,61 @@
+package stego
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+type PRNG struct {
+	seed    [sha256.Size]byte
+	counter uint64
+	buffer  []byte
+}
+
+func NewPRNG(key, domain string) *PRNG {
+	return &PRNG{
+		seed: sha256.Sum256([]byte(domain + "\x00" + key)),
+	}
+}
+
+func (p *PRNG) Uint64() uint64 {
+	if len(p.buffer) < 8 {
+		var block [sha256.Size + 8]byte
+		copy(block[:], p.seed[:])
+		binary.BigEndian.PutUint64(block[sha256.Size:], p.counter)
+		p.counter++
+
+		sum := sha256.Sum256(block[:])
+		p.buffer = sum[:]
+	}
+
+	value := binary.BigEndian.Uint64(p.buffer)
+	p.buffer = p.buffer[8:]
+	return value
+}
+
+func (p *PRNG) Intn(n int) int {
+	bound := uint64(n)
+	limit := ^uint64(0) - ^uint64(0)%bound
+
+	for {
+		value := p.Uint64()
+		if value < limit {
+			return int(value % bound)
+		}
+	}
+}
+
+func Permutation(n int, key string) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	prng := NewPRNG(key, "permutation")
+	for i := n - 1; i > 0; i-- {
+		j := prng.Intn(i + 1)
+		order[i], order[j] = order[j], order[i]
+	}
+
+	return order
+}
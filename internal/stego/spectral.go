@@ -0,0 +1,126 @@
+package stego
+
+import (
+	"fmt"
+	"math"
+	"stone-analysis/internal/dft"
+)
+
+const (
+	spectralBlockSize    = 1024
+	spectralFirstBin     = spectralBlockSize / 32
+	spectralBitsPerBlock = 32
+	spectralMargin       = 0.2
+	spectralMinMagnitude = 0.5
+)
+
+type SpectralCoefficients struct{}
+
+func NewSpectralCoefficients() *SpectralCoefficients {
+	return &SpectralCoefficients{}
+}
+
+func (m *SpectralCoefficients) ID() uint8 {
+	return MethodSpectral
+}
+
+func (m *SpectralCoefficients) Name() string {
+	return "spectral"
+}
+
+func (m *SpectralCoefficients) BlockSize() int {
+	return spectralBlockSize
+}
+
+func (m *SpectralCoefficients) Capacity(carrier *Carrier) int {
+	return len(carrier.Samples) / spectralBlockSize * spectralBitsPerBlock
+}
+
+func scaleComponent(component *dft.FrequencyComponent, magnitude float64) {
+	value := dft.Complex{Real: component.Real, Imag: component.Imag}
+	phase := value.Phase()
+
+	component.Real = magnitude * math.Cos(phase)
+	component.Imag = magnitude * math.Sin(phase)
+}
+
+func encodePair(first, second *dft.FrequencyComponent, bit uint8) {
+	a := dft.Complex{Real: first.Real, Imag: first.Imag}.Magnitude()
+	b := dft.Complex{Real: second.Real, Imag: second.Imag}.Magnitude()
+
+	if bit == 0 {
+		a, b = b, a
+	}
+
+	ratio := (1 + spectralMargin) / (1 - spectralMargin)
+	if a >= b*ratio && a >= spectralMinMagnitude {
+		return
+	}
+
+	mean := math.Max((a+b)/2, spectralMinMagnitude)
+	a = mean * (1 + spectralMargin)
+	b = mean * (1 - spectralMargin)
+
+	if bit == 0 {
+		a, b = b, a
+	}
+
+	scaleComponent(first, a)
+	scaleComponent(second, b)
+}
+
+func (m *SpectralCoefficients) Embed(carrier *Carrier, bits []uint8) error {
+	if len(bits) > m.Capacity(carrier) {
+		return ErrPayloadTooLarge
+	}
+
+	for start := 0; start < len(bits); start += spectralBitsPerBlock {
+		block := start / spectralBitsPerBlock
+		segment := carrier.Samples[block*spectralBlockSize : (block+1)*spectralBlockSize]
+
+		spectrum, err := dft.DFT(segment, carrier.SampleRate)
+		if err != nil {
+			return fmt.Errorf("dft.DFT(): %w", err)
+		}
+
+		for i := 0; i < spectralBitsPerBlock && start+i < len(bits); i++ {
+			bin := spectralFirstBin + 2*i
+			encodePair(&spectrum.Components[bin], &spectrum.Components[bin+1], bits[start+i])
+		}
+
+		rebuilt, err := dft.IDFT(spectrum)
+		if err != nil {
+			return fmt.Errorf("dft.IDFT(): %w", err)
+		}
+		copy(segment, rebuilt)
+	}
+
+	return nil
+}
+
+func (m *SpectralCoefficients) Extract(carrier *Carrier, count int) ([]uint8, error) {
+	if count > m.Capacity(carrier) {
+		return nil, ErrNotEnoughBits
+	}
+
+	bits := make([]uint8, count)
+
+	for start := 0; start < count; start += spectralBitsPerBlock {
+		block := start / spectralBitsPerBlock
+		segment := carrier.Samples[block*spectralBlockSize : (block+1)*spectralBlockSize]
+
+		spectrum, err := dft.DFT(segment, carrier.SampleRate)
+		if err != nil {
+			return nil, fmt.Errorf("dft.DFT(): %w", err)
+		}
+
+		for i := 0; i < spectralBitsPerBlock && start+i < count; i++ {
+			bin := spectralFirstBin + 2*i
+			if spectrum.Components[bin].Magnitude > spectrum.Components[bin+1].Magnitude {
+				bits[start+i] = 1
+			}
+		}
+	}
+
+	return bits, nil
+}
@@ -0,0 +1,511 @@
+package stego
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"slices"
+	"stone-analysis/internal/wav"
+	"testing"
+)
+
+func TestBitsRoundTrip(t *testing.T) {
+	data := []byte{0x00, 0xFF, 0xA5, 0x3C}
+
+	bits := BytesToBits(data)
+	if len(bits) != len(data)*8 {
+		t.Fatalf("Expected %d bits, got %d", len(data)*8, len(bits))
+	}
+
+	if bits[8] != 1 || bits[16] != 1 || bits[17] != 0 {
+		t.Errorf("Expected MSB-first bit order, got %v", bits[8:24])
+	}
+
+	result := BitsToBytes(bits)
+	if !bytes.Equal(result, data) {
+		t.Errorf("Expected %v, got %v", data, result)
+	}
+}
+
+func TestLSBRoundTrip(t *testing.T) {
+	const fullScale = 32768.0
+	samples := make([]float64, 128)
+	for i := range samples {
+		samples[i] = float64(i*517%65536-32768) / fullScale
+	}
+	original := make([]float64, len(samples))
+	copy(original, samples)
+
+	message := []byte("hidden")
+	if err := EmbedLSB(samples, fullScale, BytesToBits(message), nil); err != nil {
+		t.Fatalf("EmbedLSB failed: %v", err)
+	}
+
+	for i := range samples {
+		if diff := (samples[i] - original[i]) * fullScale; diff > 1 || diff < -1 {
+			t.Errorf("Sample %d changed by %.0f steps, expected at most 1", i, diff)
+		}
+	}
+
+	bits, err := ExtractLSB(samples, fullScale, len(message)*8, nil)
+	if err != nil {
+		t.Fatalf("ExtractLSB failed: %v", err)
+	}
+
+	if result := BitsToBytes(bits); !bytes.Equal(result, message) {
+		t.Errorf("Expected %q, got %q", message, result)
+	}
+}
+
+func TestLSBCapacity(t *testing.T) {
+	samples := make([]float64, 8)
+
+	err := EmbedLSB(samples, 32768, make([]uint8, 9), nil)
+	if err != ErrPayloadTooLarge {
+		t.Errorf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+
+	_, err = ExtractLSB(samples, 32768, 9, nil)
+	if err != ErrNotEnoughBits {
+		t.Errorf("Expected ErrNotEnoughBits, got %v", err)
+	}
+}
+
+func TestPermutation(t *testing.T) {
+	order := Permutation(1000, "stego key")
+
+	seen := make([]bool, len(order))
+	for _, index := range order {
+		if index < 0 || index >= len(order) || seen[index] {
+			t.Fatalf("Permutation is not a bijection, index %d repeated or out of range", index)
+		}
+		seen[index] = true
+	}
+
+	again := Permutation(1000, "stego key")
+	other := Permutation(1000, "other key")
+	sameAsOther := 0
+	for i := range order {
+		if order[i] != again[i] {
+			t.Fatalf("Permutation is not deterministic at index %d", i)
+		}
+		if order[i] == other[i] {
+			sameAsOther++
+		}
+	}
+
+	if sameAsOther > 20 {
+		t.Errorf("Different keys produced %d identical positions", sameAsOther)
+	}
+
+	if order[0] < 100 && order[1] < 100 && order[2] < 100 {
+		t.Errorf("Permutation does not spread the first slots: %v", order[:3])
+	}
+}
+
+func TestLSBKeyedOrder(t *testing.T) {
+	const fullScale = 32768.0
+	samples := make([]float64, 512)
+	order := Permutation(len(samples), "key")
+	message := []byte("scattered")
+
+	if err := EmbedLSB(samples, fullScale, BytesToBits(message), order); err != nil {
+		t.Fatalf("EmbedLSB failed: %v", err)
+	}
+
+	bits, err := ExtractLSB(samples, fullScale, len(message)*8, order)
+	if err != nil {
+		t.Fatalf("ExtractLSB failed: %v", err)
+	}
+	if result := BitsToBytes(bits); !bytes.Equal(result, message) {
+		t.Errorf("Expected %q, got %q", message, result)
+	}
+
+	sequential, _ := ExtractLSB(samples, fullScale, len(message)*8, nil)
+	if bytes.Equal(BitsToBytes(sequential), message) {
+		t.Error("Message should not be readable in sequential order")
+	}
+}
+
+func testSignal(n int) []float64 {
+	samples := make([]float64, n)
+	for i := range samples {
+		t := float64(i) / 48000
+		samples[i] = 0.2*math.Sin(2*math.Pi*440*t) + 0.1*math.Sin(2*math.Pi*2500*t) +
+			0.05*math.Sin(2*math.Pi*6100*t+float64(i%7))
+	}
+	return samples
+}
+
+func quantize(samples []float64, fullScale float64, stripLSB bool) {
+	for i, sample := range samples {
+		value := int(math.Round(sample * fullScale))
+		if stripLSB {
+			value &^= 1
+		}
+		samples[i] = float64(value) / fullScale
+	}
+}
+
+func TestPhaseCodingRoundTrip(t *testing.T) {
+	carrier := &Carrier{Samples: testSignal(4 * 8192), SampleRate: 48000, FullScale: 32768}
+	method := NewPhaseCoding()
+
+	message := []byte("phase coded payload")
+	if err := method.Embed(carrier, BytesToBits(message)); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	quantize(carrier.Samples, carrier.FullScale, true)
+
+	bits, err := method.Extract(carrier, len(message)*8)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result := BitsToBytes(bits); !bytes.Equal(result, message) {
+		t.Errorf("Expected %q after LSB stripping, got %q", message, result)
+	}
+}
+
+func TestPhaseCodingCapacity(t *testing.T) {
+	method := NewPhaseCoding()
+
+	short := &Carrier{Samples: make([]float64, 1000), SampleRate: 48000, FullScale: 32768}
+	if method.Capacity(short) != 0 {
+		t.Errorf("Expected no capacity for a carrier shorter than a segment")
+	}
+
+	if err := method.Embed(short, []uint8{1}); err != ErrPayloadTooLarge {
+		t.Errorf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+}
+
+func TestNewMethod(t *testing.T) {
+	for _, name := range Names() {
+		method, err := New(name, Config{})
+		if err != nil {
+			t.Fatalf("New(%s) failed: %v", name, err)
+		}
+		if method.Name() != name {
+			t.Errorf("Expected method %s, got %s", name, method.Name())
+		}
+	}
+
+	if _, err := New("unknown", Config{}); !errors.Is(err, ErrUnknownMethod) {
+		t.Errorf("Expected ErrUnknownMethod, got %v", err)
+	}
+}
+
+func TestSpreadSpectrumRobustness(t *testing.T) {
+	message := []byte("watermark")
+	attacks := map[string]func([]float64){
+		"requantize8": func(samples []float64) { quantize(samples, 128, false) },
+		"gain": func(samples []float64) {
+			for i := range samples {
+				samples[i] *= 0.5
+			}
+		},
+		"noise": func(samples []float64) {
+			prng := NewPRNG("noise", "test")
+			for i := range samples {
+				samples[i] += (float64(prng.Intn(2001)) - 1000) / 1000 * 0.002
+			}
+		},
+	}
+
+	for name, attack := range attacks {
+		carrier := &Carrier{Samples: testSignal(len(message) * 8 * 1024), SampleRate: 48000, FullScale: 32768}
+		method := NewSpreadSpectrum("key", 0)
+
+		if err := method.Embed(carrier, BytesToBits(message)); err != nil {
+			t.Fatalf("Embed failed: %v", err)
+		}
+		attack(carrier.Samples)
+
+		bits, err := method.Extract(carrier, len(message)*8)
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		if result := BitsToBytes(bits); !bytes.Equal(result, message) {
+			t.Errorf("%s: expected %q, got %q", name, message, result)
+		}
+	}
+}
+
+func TestSpreadSpectrumWrongKey(t *testing.T) {
+	carrier := &Carrier{Samples: testSignal(64 * 1024), SampleRate: 48000, FullScale: 32768}
+	message := []byte("keyed")
+
+	if err := NewSpreadSpectrum("right", 0).Embed(carrier, BytesToBits(message)); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	bits, err := NewSpreadSpectrum("wrong", 0).Extract(carrier, len(message)*8)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if bytes.Equal(BitsToBytes(bits), message) {
+		t.Error("Message should not be recoverable with a different key")
+	}
+}
+
+func noisySignal(n int) []float64 {
+	samples := testSignal(n)
+	prng := NewPRNG("signal", "test")
+	for i := range samples {
+		samples[i] += (float64(prng.Intn(2001)) - 1000) / 1000 * 0.05
+	}
+	return samples
+}
+
+func TestEchoHidingRoundTrip(t *testing.T) {
+	message := []byte("echo hidden")
+	carrier := &Carrier{Samples: noisySignal(len(message) * 8 * 2048), SampleRate: 48000, FullScale: 32768}
+	method := NewEchoHiding()
+
+	if err := method.Embed(carrier, BytesToBits(message)); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	quantize(carrier.Samples, carrier.FullScale, true)
+
+	bits, err := method.Extract(carrier, len(message)*8)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result := BitsToBytes(bits); !bytes.Equal(result, message) {
+		t.Errorf("Expected %q, got %q", message, result)
+	}
+
+	if err := method.Embed(carrier, make([]uint8, method.Capacity(carrier)+1)); err != ErrPayloadTooLarge {
+		t.Errorf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+}
+
+func TestEchoHidingHeadroom(t *testing.T) {
+	message := []byte("loud")
+	samples := noisySignal(len(message) * 8 * 2048)
+	for i := range samples {
+		samples[i] *= 0.98 / 0.4
+	}
+	carrier := &Carrier{Samples: samples, SampleRate: 48000, FullScale: 32768}
+	method := NewEchoHiding()
+
+	if err := method.Embed(carrier, BytesToBits(message)); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	limit := 1 - 1/carrier.FullScale
+	for i, sample := range carrier.Samples {
+		if math.Abs(sample) > limit+1e-9 {
+			t.Fatalf("Sample %d is %.4f, beyond full scale", i, sample)
+		}
+	}
+
+	quantize(carrier.Samples, carrier.FullScale, false)
+
+	bits, err := method.Extract(carrier, len(message)*8)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result := BitsToBytes(bits); !bytes.Equal(result, message) {
+		t.Errorf("Expected %q, got %q", message, result)
+	}
+}
+
+func TestSpectralCoefficientsRobustness(t *testing.T) {
+	message := []byte("spectral coefficients")
+	attacks := map[string]func([]float64){
+		"requantize16": func(samples []float64) { quantize(samples, 32768, true) },
+		"lowpass": func(samples []float64) {
+			previous := 0.0
+			for i := range samples {
+				current := samples[i]
+				samples[i] = 0.75*current + 0.25*previous
+				previous = current
+			}
+		},
+		"gain": func(samples []float64) {
+			for i := range samples {
+				samples[i] *= 0.7
+			}
+		},
+	}
+
+	for name, attack := range attacks {
+		carrier := &Carrier{Samples: noisySignal(6 * 1024), SampleRate: 48000, FullScale: 32768}
+		method := NewSpectralCoefficients()
+
+		if err := method.Embed(carrier, BytesToBits(message)); err != nil {
+			t.Fatalf("Embed failed: %v", err)
+		}
+		attack(carrier.Samples)
+
+		bits, err := method.Extract(carrier, len(message)*8)
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		if result := BitsToBytes(bits); !bytes.Equal(result, message) {
+			t.Errorf("%s: expected %q, got %q", name, message, result)
+		}
+	}
+}
+
+func TestAdaptiveLSBSkipsSilence(t *testing.T) {
+	samples := noisySignal(8 * 1024)
+	for i := 2 * 1024; i < 5*1024; i++ {
+		samples[i] = 0
+	}
+	quantize(samples, 32768, false)
+	original := make([]float64, len(samples))
+	copy(original, samples)
+
+	carrier := &Carrier{Samples: samples, SampleRate: 48000, FullScale: 32768}
+	method := NewAdaptiveLSB()
+	if capacity := method.Capacity(carrier); capacity == 0 || capacity%1024 != 0 || capacity > 5*1024*3 {
+		t.Fatalf("Unexpected capacity %d", capacity)
+	}
+
+	message := bytes.Repeat([]byte("adaptive"), 150)
+	if err := method.Embed(carrier, BytesToBits(message)); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	for i := range samples {
+		diff := math.Abs(samples[i]-original[i]) * 32768
+		if i >= 2*1024 && i < 5*1024 && diff != 0 {
+			t.Fatalf("Silent sample %d was modified", i)
+		}
+		if diff > 7 {
+			t.Fatalf("Sample %d changed by %.0f steps", i, diff)
+		}
+	}
+
+	bits, err := NewAdaptiveLSB().Extract(&Carrier{Samples: samples, SampleRate: 48000, FullScale: 32768}, len(message)*8)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result := BitsToBytes(bits); !bytes.Equal(result, message) {
+		t.Error("Message mismatch after adaptive round trip")
+	}
+
+	if err := method.Embed(carrier, make([]uint8, method.Capacity(carrier)+1)); err != ErrPayloadTooLarge {
+		t.Errorf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+}
+
+func TestAdaptiveLSBReusedCarrier(t *testing.T) {
+	samples := noisySignal(8 * 1024)
+	quantize(samples, 32768, false)
+
+	carrier := &Carrier{Samples: samples, SampleRate: 48000, FullScale: 32768}
+	method := NewAdaptiveLSB()
+	before := method.Capacity(carrier)
+
+	for i := 0; i < 4*1024; i++ {
+		samples[i] = 0
+	}
+	after := method.Capacity(carrier)
+	if after >= before {
+		t.Fatalf("Expected capacity to drop after silencing half the carrier, got %d then %d", before, after)
+	}
+
+	message := bytes.Repeat([]uint8{1}, after)
+	if err := method.Embed(carrier, message); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	for i := 0; i < 4*1024; i++ {
+		if samples[i] != 0 {
+			t.Fatalf("Silenced sample %d was modified", i)
+		}
+	}
+}
+
+func TestLayoutGatherScatter(t *testing.T) {
+	samples := []float64{1, 2, 3, 4, 5, 6}
+
+	all, err := NewLayout(2, 0)
+	if err != nil {
+		t.Fatalf("NewLayout failed: %v", err)
+	}
+	gathered := all.Gather(samples, 1)
+	if expected := []float64{1, 2, 3, 4, 5, 6}; !slices.Equal(gathered, expected) {
+		t.Errorf("Expected %v, got %v", expected, gathered)
+	}
+
+	second, _ := NewLayout(2, 2)
+	right := second.Gather(samples, 1)
+	if expected := []float64{2, 4, 6}; !slices.Equal(right, expected) {
+		t.Errorf("Expected %v, got %v", expected, right)
+	}
+
+	right[1] = -4
+	second.Scatter(samples, right, 1)
+	if expected := []float64{1, 2, 3, -4, 5, 6}; !slices.Equal(samples, expected) {
+		t.Errorf("Expected %v, got %v", expected, samples)
+	}
+
+	blocks := all.Gather(samples, 2)
+	if expected := []float64{1, 3, 2, -4}; !slices.Equal(blocks, expected) {
+		t.Errorf("Expected %v, got %v", expected, blocks)
+	}
+
+	blocks[2] = -2
+	all.Scatter(samples, blocks, 2)
+	if expected := []float64{1, -2, 3, -4, 5, 6}; !slices.Equal(samples, expected) {
+		t.Errorf("Expected %v after block scatter, got %v", expected, samples)
+	}
+
+	all.Scatter(samples, all.Gather(samples, 1), 1)
+	if expected := []float64{1, -2, 3, -4, 5, 6}; !slices.Equal(samples, expected) {
+		t.Errorf("Expected %v after round trip, got %v", expected, samples)
+	}
+
+	if _, err := NewLayout(2, 3); !errors.Is(err, ErrInvalidChannel) {
+		t.Errorf("Expected ErrInvalidChannel, got %v", err)
+	}
+	if layouts := Layouts(1); len(layouts) != 1 {
+		t.Errorf("Expected a single layout for mono, got %d", len(layouts))
+	}
+}
+
+func TestLayoutShortMessageUsesBothChannels(t *testing.T) {
+	left, right := noisySignal(16384), noisySignal(16384)
+	for i := range right {
+		right[i] *= 0.5
+	}
+	quantize(left, 32768, true)
+	quantize(right, 32768, true)
+	original := wav.Interleave([][]float64{left, right})
+
+	layout, _ := NewLayout(2, 0)
+	for _, method := range []Method{NewLSB(""), NewSpectralCoefficients()} {
+		samples := slices.Clone(original)
+		block := BlockSize(method)
+		carrier := &Carrier{Samples: layout.Gather(samples, block), SampleRate: 48000, FullScale: 32768}
+
+		message := BytesToBits([]byte("stereo"))
+		if err := method.Embed(carrier, message); err != nil {
+			t.Fatalf("%s: Embed failed: %v", method.Name(), err)
+		}
+		layout.Scatter(samples, carrier.Samples, block)
+
+		touched := make([]bool, 2)
+		for i := range samples {
+			if samples[i] != original[i] {
+				touched[i%2] = true
+			}
+		}
+		if !touched[0] || !touched[1] {
+			t.Errorf("%s: expected a short message to touch both channels, got %v", method.Name(), touched)
+		}
+
+		bits, err := method.Extract(&Carrier{Samples: layout.Gather(samples, block), SampleRate: 48000, FullScale: 32768}, len(message))
+		if err != nil {
+			t.Fatalf("%s: Extract failed: %v", method.Name(), err)
+		}
+		if result := BitsToBytes(bits); string(result) != "stereo" {
+			t.Errorf("%s: expected %q, got %q", method.Name(), "stereo", result)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package stego
+
+import "errors"
+
+const DefaultMethod = "lsb"
+
+const (
+	MethodLSB            uint8 = 1
+	MethodPhase          uint8 = 2
+	MethodSpreadSpectrum uint8 = 3
+	MethodEcho           uint8 = 4
+	MethodSpectral       uint8 = 5
+	MethodAdaptive       uint8 = 6
+)
+
+var (
+	ErrPayloadTooLarge = errors.New("payload does not fit in carrier")
+	ErrNotEnoughBits   = errors.New("carrier holds fewer bits than requested")
+	ErrUnknownMethod   = errors.New("unknown embedding method")
+	ErrInvalidChannel  = errors.New("invalid channel")
+)
+
+type Carrier struct {
+	Samples    []float64
+	SampleRate float64
+	FullScale  float64
+}
+
+type Config struct {
+	Key      string
+	Strength float64
+}
+
+type Method interface {
+	ID() uint8
+	Name() string
+	Capacity(carrier *Carrier) int
+	Embed(carrier *Carrier, bits []uint8) error
+	Extract(carrier *Carrier, count int) ([]uint8, error)
+}
+
+type BlockMethod interface {
+	Method
+	BlockSize() int
+}
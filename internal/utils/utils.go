@@ -2,19 +2,42 @@ package utils
 
 import (
 	"fmt"
+	"io"
 	"os"
 )
 
 func DisplayHelp() {
 	fmt.Fprintf(
 		os.Stdout,
-		"USAGE\n%s [--analyze IN_FILE N | --cypher IN_FILE OUT_FILE MESSAGE | --decypher IN_FILE]\n\n",
+		"USAGE\n%s [--analyze [OPTIONS] IN_FILE N | --cypher [OPTIONS] IN_FILE OUT_FILE [MESSAGE] | --decypher [OPTIONS] IN_FILE | --check [OPTIONS] IN_FILE | --capacity [OPTIONS] IN_FILE | --detect IN_FILE | --fsk-encode [OPTIONS] OUT_FILE [MESSAGE] | --fsk-decode [OPTIONS] IN_FILE | --dtmf-encode DIGITS OUT_FILE | --dtmf-decode IN_FILE | --robustness [OPTIONS] IN_FILE | --compare IN_FILE IN_FILE]\n\n",
 		os.Args[0],
 	)
 	fmt.Println("\tIN_FILE\tAn audio file to be analyzed")
 	fmt.Println("\tOUT_FILE\tOutput audio file of the cypher mode")
 	fmt.Println("\tMESSAGE\tThe message to hide in the audio file")
 	fmt.Println("\tN\tNumber of top frequencies to display")
+	fmt.Println("\tDIGITS\tDTMF digits to synthesize (0-9, A-D, * and #)")
+	fmt.Println()
+	fmt.Println("OPTIONS")
+	fmt.Println("\t--input FILE\tHide FILE (- for stdin) instead of MESSAGE")
+	fmt.Println("\t--output FILE\tWrite the recovered payload to FILE (- for stdout)")
+	fmt.Println("\t--key, --passphrase KEY\tEncrypt or decrypt the hidden payload with KEY")
+	fmt.Println("\t--method NAME\tEmbedding method: lsb (default), phase, dsss, echo, spectral or adaptive")
+	fmt.Println("\t--sign-key FILE\tSign the payload with the Ed25519 private key in FILE (PEM)")
+	fmt.Println("\t--verify-key FILE\tCheck the payload signature with the Ed25519 public key in FILE (PEM)")
+	fmt.Println("\t--compress\tCompress the payload with DEFLATE before embedding")
+	fmt.Println("\t--quality\tPrint SNR, PSNR and spectral distance of the cypher output")
+	fmt.Println("\t--fec NAME\tError correction: none (default), hamming or rs")
+	fmt.Println("\t--strength S\tAmplitude of the dsss watermark (default 0.003)")
+	fmt.Println("\t--stego-key KEY\tScatter the payload over samples chosen from KEY")
+	fmt.Println("\t--band NAME\tFSK band: audible (default, 1200/2200 Hz) or ultrasonic (18500/19500 Hz)")
+	fmt.Println("\t--baud N\tFSK symbol rate in baud (default 100)")
+	fmt.Println("\t--view NAME\tChannels analyzed: downmix (default), each or midside")
+	fmt.Println("\t--rate-policy NAME\tSample rates accepted: strict (default, 48 kHz only) or permissive (8-384 kHz)")
+	fmt.Println("\t--channel N\tHide the payload in channel N only (default 0, all channels)")
+	fmt.Println()
+	fmt.Println("\t--check exits with 0 when a payload is found, 1 when none is present")
+	fmt.Println("\tand 84 when the payload is corrupted")
 }
 
 func CheckFileExists(filePath string) error {
@@ -39,3 +62,23 @@ func CheckFileExists(filePath string) error {
 	}
 	return nil
 }
+
+func ReadInput(filePath string) ([]byte, error) {
+	if filePath == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("error: could not read standard input: %v", err)
+		}
+		return data, nil
+	}
+
+	if err := CheckFileExists(filePath); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error: could not read input file '%s': %v", filePath, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,100 @@
+package wav
+
+func Deinterleave(samples []float64, numChannels int) [][]float64 {
+	if numChannels < 1 {
+		return nil
+	}
+
+	frames := len(samples) / numChannels
+	channels := make([][]float64, numChannels)
+	for c := range channels {
+		channels[c] = make([]float64, frames)
+		for i := range channels[c] {
+			channels[c][i] = samples[i*numChannels+c]
+		}
+	}
+
+	return channels
+}
+
+func Interleave(channels [][]float64) []float64 {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	frames := len(channels[0])
+	for _, channel := range channels[1:] {
+		frames = min(frames, len(channel))
+	}
+
+	samples := make([]float64, frames*len(channels))
+	for c, channel := range channels {
+		for i := 0; i < frames; i++ {
+			samples[i*len(channels)+c] = channel[i]
+		}
+	}
+
+	return samples
+}
+
+func (w *WavFile) NumFrames() int {
+	if w.FmtChunk.NumChannels == 0 {
+		return 0
+	}
+	return len(w.Samples) / int(w.FmtChunk.NumChannels)
+}
+
+func (w *WavFile) Frame(i int) []float64 {
+	numChannels := int(w.FmtChunk.NumChannels)
+	return w.Samples[i*numChannels : (i+1)*numChannels]
+}
+
+func (w *WavFile) Channel(c int) []float64 {
+	numChannels := int(w.FmtChunk.NumChannels)
+	channel := make([]float64, w.NumFrames())
+	for i := range channel {
+		channel[i] = w.Samples[i*numChannels+c]
+	}
+	return channel
+}
+
+func (w *WavFile) Channels() [][]float64 {
+	return Deinterleave(w.Samples, int(w.FmtChunk.NumChannels))
+}
+
+func (w *WavFile) SetChannels(channels [][]float64) {
+	width := SampleWidth(w.FmtChunk)
+	w.Samples = Interleave(channels)
+	w.FmtChunk.NumChannels = uint16(len(channels))
+	w.FmtChunk.BlockAlign = uint16(len(channels) * width)
+	w.FmtChunk.ByteRate = w.FmtChunk.SampleRate * uint32(w.FmtChunk.BlockAlign)
+}
+
+func Downmix(channels [][]float64) []float64 {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	mix := make([]float64, len(channels[0]))
+	for _, channel := range channels {
+		for i := range mix {
+			mix[i] += channel[i]
+		}
+	}
+
+	for i := range mix {
+		mix[i] /= float64(len(channels))
+	}
+
+	return mix
+}
+
+func MidSide(left, right []float64) ([]float64, []float64) {
+	mid := make([]float64, len(left))
+	side := make([]float64, len(left))
+	for i := range left {
+		mid[i] = (left[i] + right[i]) / 2
+		side[i] = (left[i] - right[i]) / 2
+	}
+	return mid, side
+}
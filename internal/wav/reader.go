@@ -10,6 +10,7 @@ func NewWavReader() *WavReader {
 	return &WavReader{
 		Current:    0,
 		File:       nil,
+		Format:     DefaultFormat(),
 		endianness: binary.LittleEndian,
 	}
 }
@@ -101,7 +102,36 @@ func (w *WavReader) ReadFmtChunk() (FmtSubChunk, error) {
 		return FmtSubChunk{}, fmt.Errorf("binary.Read(w.File, w.endianness, &fmtChunk.BitsPerSample): %w", err)
 	}
 
-	w.Current += 16
+	if fmtChunk.SubChunkSize >= fmtExtendedSize {
+		err = binary.Read(w.File, w.endianness, &fmtChunk.ExtensionSize)
+		if err != nil {
+			return FmtSubChunk{}, fmt.Errorf("binary.Read(w.File, w.endianness, &fmtChunk.ExtensionSize): %w", err)
+		}
+	}
+
+	if fmtChunk.AudioFormat == FormatExtensible {
+		if fmtChunk.ExtensionSize < extensionSize || fmtChunk.SubChunkSize < fmtExtensibleSize {
+			return FmtSubChunk{}, fmt.Errorf("%w: extensible fmt chunk of %d bytes", ErrUnsupportedAudioFormat, fmtChunk.SubChunkSize)
+		}
+
+		err = binary.Read(w.File, w.endianness, &fmtChunk.ValidBitsPerSample)
+		if err != nil {
+			return FmtSubChunk{}, fmt.Errorf("binary.Read(w.File, w.endianness, &fmtChunk.ValidBitsPerSample): %w", err)
+		}
+
+		err = binary.Read(w.File, w.endianness, &fmtChunk.ChannelMask)
+		if err != nil {
+			return FmtSubChunk{}, fmt.Errorf("binary.Read(w.File, w.endianness, &fmtChunk.ChannelMask): %w", err)
+		}
+
+		err = binary.Read(w.File, w.endianness, &fmtChunk.SubFormat)
+		if err != nil {
+			return FmtSubChunk{}, fmt.Errorf("binary.Read(w.File, w.endianness, &fmtChunk.SubFormat): %w", err)
+		}
+	}
+
+	w.Current += 8 + int64(fmtChunk.SubChunkSize)
+	w.Format = fmtChunk
 
 	return fmtChunk, nil
 }
@@ -140,17 +170,25 @@ func (w *WavReader) ReadDataChunk() (DataSubChunk, error) {
 }
 
 func (w *WavReader) ConvertToSamples(data []byte) []float64 {
-	var samples = make([]float64, len(data)/2)
+	width := SampleWidth(w.Format)
+	var samples = make([]float64, len(data)/width)
+	fullScale := FullScale(w.Format.BitsPerSample)
+
+	if w.Format.SampleFormat() == FormatIEEEFloat {
+		for i := 0; i < len(samples); i++ {
+			samples[i] = decodeFloat(data[i*width : (i+1)*width])
+		}
+		return samples
+	}
 
 	for i := 0; i < len(samples); i++ {
-		sample := int16(binary.LittleEndian.Uint16(data[i*2 : (i+1)*2]))
-		samples[i] = float64(sample) / 32768.0
+		samples[i] = float64(decodeSample(data[i*width:(i+1)*width])) / fullScale
 	}
 
 	return samples
 }
 
-func ReadWavFile(filePath string) (*WavFile, error) {
+func ReadWavFile(filePath string, policy ValidationPolicy) (*WavFile, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("os.Open(%s): %w", filePath, err)
@@ -177,8 +215,8 @@ func ReadWavFile(filePath string) (*WavFile, error) {
 		return nil, fmt.Errorf("reader.ReadDataChunk(): %w", err)
 	}
 
-	if err := ValidateWavFormat(wavFile); err != nil {
-		return nil, fmt.Errorf("ValidateWavFormat(): %w", err)
+	if err := policy.Validate(wavFile); err != nil {
+		return nil, fmt.Errorf("policy.Validate(): %w", err)
 	}
 
 	wavFile.Samples = reader.ConvertToSamples(wavFile.DataChunk.Data)
@@ -6,11 +6,44 @@ import (
 	"os"
 )
 
+const (
+	FormatPCM        uint16 = 1
+	FormatIEEEFloat  uint16 = 3
+	FormatExtensible uint16 = 0xFFFE
+)
+
+const (
+	fmtBaseSize       = 16
+	fmtExtendedSize   = 18
+	fmtExtensibleSize = 40
+	extensionSize     = fmtExtensibleSize - fmtExtendedSize
+)
+
+type GUID [16]byte
+
+var (
+	SubFormatPCM       = GUID{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71}
+	SubFormatIEEEFloat = GUID{0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71}
+)
+
 var (
 	ErrUnsupportedAudioFormat = errors.New("unsupported audio format")
 	ErrInvalidNumChannels     = errors.New("invalid number of channels")
 	ErrInvalidSampleRate      = errors.New("invalid sample rate")
 	ErrInvalidBitsPerSample   = errors.New("invalid bits per sample")
+	ErrInvalidBlockAlign      = errors.New("block align does not match the sample format")
+	ErrUnknownPolicy          = errors.New("unknown validation policy")
+)
+
+type ValidationPolicy struct {
+	Name          string
+	MinSampleRate uint32
+	MaxSampleRate uint32
+}
+
+var (
+	StrictPolicy     = ValidationPolicy{Name: "strict", MinSampleRate: 48000, MaxSampleRate: 48000}
+	PermissivePolicy = ValidationPolicy{Name: "permissive", MinSampleRate: 8000, MaxSampleRate: 384000}
 )
 
 type FourCC [4]byte
@@ -38,6 +71,35 @@ type FmtSubChunk struct {
 	ByteRate      uint32
 	BlockAlign    uint16
 	BitsPerSample uint16
+
+	ExtensionSize      uint16
+	ValidBitsPerSample uint16
+	ChannelMask        uint32
+	SubFormat          GUID
+}
+
+func (f FmtSubChunk) SampleFormat() uint16 {
+	if f.AudioFormat != FormatExtensible {
+		return f.AudioFormat
+	}
+
+	switch f.SubFormat {
+	case SubFormatPCM:
+		return FormatPCM
+	case SubFormatIEEEFloat:
+		return FormatIEEEFloat
+	}
+	return FormatExtensible
+}
+
+func (f FmtSubChunk) Size() uint32 {
+	switch {
+	case f.AudioFormat == FormatExtensible:
+		return fmtExtensibleSize
+	case f.AudioFormat != FormatPCM:
+		return fmtExtendedSize
+	}
+	return fmtBaseSize
 }
 
 type DataSubChunk struct {
@@ -56,11 +118,13 @@ type WavFile struct {
 type WavReader struct {
 	Current    int64
 	File       *os.File
+	Format     FmtSubChunk
 	endianness binary.ByteOrder
 }
 
 type WavWriter struct {
 	File       *os.File
+	Format     FmtSubChunk
 	endianness binary.ByteOrder
 	current    int64
 	headerSize int64
@@ -3,29 +3,142 @@ package wav
 import (
 	"encoding/binary"
 	"fmt"
+	"math"
 	"os"
 )
 
+func ParsePolicy(name string) (ValidationPolicy, error) {
+	for _, policy := range []ValidationPolicy{StrictPolicy, PermissivePolicy} {
+		if policy.Name == name {
+			return policy, nil
+		}
+	}
+	return ValidationPolicy{}, fmt.Errorf("%w: %s", ErrUnknownPolicy, name)
+}
+
 func ValidateWavFormat(wavFile *WavFile) error {
-	if wavFile.FmtChunk.AudioFormat != 1 {
+	return StrictPolicy.Validate(wavFile)
+}
+
+func (p ValidationPolicy) Validate(wavFile *WavFile) error {
+	if p == (ValidationPolicy{}) {
+		p = StrictPolicy
+	}
+
+	sampleFormat := wavFile.FmtChunk.SampleFormat()
+	if sampleFormat != FormatPCM && sampleFormat != FormatIEEEFloat {
 		return ErrUnsupportedAudioFormat
 	}
 
-	if wavFile.FmtChunk.NumChannels != 1 {
+	if wavFile.FmtChunk.NumChannels == 0 {
 		return ErrInvalidNumChannels
 	}
 
-	if wavFile.FmtChunk.SampleRate != 48000 {
+	if wavFile.FmtChunk.SampleRate < p.MinSampleRate || wavFile.FmtChunk.SampleRate > p.MaxSampleRate {
 		return ErrInvalidSampleRate
 	}
 
-	if wavFile.FmtChunk.BitsPerSample != 16 {
+	switch {
+	case sampleFormat == FormatIEEEFloat:
+		if wavFile.FmtChunk.BitsPerSample != 32 && wavFile.FmtChunk.BitsPerSample != 64 {
+			return ErrInvalidBitsPerSample
+		}
+	default:
+		switch wavFile.FmtChunk.BitsPerSample {
+		case 8, 16, 24, 32:
+		default:
+			return ErrInvalidBitsPerSample
+		}
+	}
+
+	if wavFile.FmtChunk.ValidBitsPerSample > wavFile.FmtChunk.BitsPerSample {
 		return ErrInvalidBitsPerSample
 	}
 
+	if wavFile.FmtChunk.BlockAlign != wavFile.FmtChunk.NumChannels*wavFile.FmtChunk.BitsPerSample/8 {
+		return ErrInvalidBlockAlign
+	}
+
 	return nil
 }
 
+func DefaultFormat() FmtSubChunk {
+	return FmtSubChunk{
+		SubChunkID:    FourCC{'f', 'm', 't', ' '},
+		SubChunkSize:  16,
+		AudioFormat:   FormatPCM,
+		NumChannels:   1,
+		SampleRate:    48000,
+		ByteRate:      48000 * 2,
+		BlockAlign:    2,
+		BitsPerSample: 16,
+	}
+}
+
+func FullScale(bitsPerSample uint16) float64 {
+	return float64(uint64(1) << (bitsPerSample - 1))
+}
+
+func SampleScale(format FmtSubChunk) float64 {
+	if format.SampleFormat() == FormatIEEEFloat {
+		if format.BitsPerSample == 32 {
+			return 1 << 23
+		}
+		return 1 << 52
+	}
+	if format.ValidBitsPerSample > 0 {
+		return FullScale(format.ValidBitsPerSample)
+	}
+	return FullScale(format.BitsPerSample)
+}
+
+func SampleWidth(format FmtSubChunk) int {
+	if format.NumChannels > 0 && format.BlockAlign > 0 {
+		return int(format.BlockAlign / format.NumChannels)
+	}
+	return int(format.BitsPerSample+7) / 8
+}
+
+func decodeFloat(data []byte) float64 {
+	if len(data) == 4 {
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(data)))
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(data))
+}
+
+func encodeFloat(data []byte, value float64) {
+	if len(data) == 4 {
+		binary.LittleEndian.PutUint32(data, math.Float32bits(float32(value)))
+		return
+	}
+	binary.LittleEndian.PutUint64(data, math.Float64bits(value))
+}
+
+func decodeSample(data []byte) int64 {
+	if len(data) == 1 {
+		return int64(data[0]) - 128
+	}
+
+	var value uint64
+	for i, b := range data {
+		value |= uint64(b) << (8 * i)
+	}
+
+	shift := 64 - 8*len(data)
+	return int64(value<<shift) >> shift
+}
+
+func encodeSample(data []byte, value int64) {
+	if len(data) == 1 {
+		data[0] = byte(value + 128)
+		return
+	}
+
+	for i := range data {
+		data[i] = byte(value >> (8 * i))
+	}
+}
+
 //lint:ignore U1000 useful later
 func readBytes(file *os.File, n int) ([]byte, error) {
 	buffer := make([]byte, n)
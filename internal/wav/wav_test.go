@@ -1,6 +1,7 @@
 package wav
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -62,7 +63,7 @@ func TestReadWavFile(t *testing.T) {
 	testFilePath := filepath.Join(tmpDir, "test.wav")
 	createTestWavFile(t, testFilePath)
 
-	wavFile, err := ReadWavFile(testFilePath)
+	wavFile, err := ReadWavFile(testFilePath, StrictPolicy)
 	if err != nil {
 		t.Fatalf("Failed to read WAV file: %v", err)
 	}
@@ -126,6 +127,7 @@ func TestValidateWavFormat(t *testing.T) {
 			AudioFormat:   1,
 			NumChannels:   1,
 			SampleRate:    48000,
+			BlockAlign:    2,
 			BitsPerSample: 16,
 		},
 	}
@@ -143,12 +145,19 @@ func TestValidateWavFormat(t *testing.T) {
 	}
 
 	invalidChannels := *validWav
-	invalidChannels.FmtChunk.NumChannels = 2
+	invalidChannels.FmtChunk.NumChannels = 0
 	err = ValidateWavFormat(&invalidChannels)
 	if err != ErrInvalidNumChannels {
 		t.Errorf("Expected ErrInvalidNumChannels, got %v", err)
 	}
 
+	stereo := *validWav
+	stereo.FmtChunk.NumChannels = 2
+	stereo.FmtChunk.BlockAlign = 4
+	if err := ValidateWavFormat(&stereo); err != nil {
+		t.Errorf("Expected stereo WAV to be valid, got error: %v", err)
+	}
+
 	invalidRate := *validWav
 	invalidRate.FmtChunk.SampleRate = 44100
 	err = ValidateWavFormat(&invalidRate)
@@ -157,11 +166,27 @@ func TestValidateWavFormat(t *testing.T) {
 	}
 
 	invalidBits := *validWav
-	invalidBits.FmtChunk.BitsPerSample = 8
+	invalidBits.FmtChunk.BitsPerSample = 12
 	err = ValidateWavFormat(&invalidBits)
 	if err != ErrInvalidBitsPerSample {
 		t.Errorf("Expected ErrInvalidBitsPerSample, got %v", err)
 	}
+
+	for _, bits := range []uint16{8, 24, 32} {
+		depth := *validWav
+		depth.FmtChunk.BitsPerSample = bits
+		depth.FmtChunk.BlockAlign = bits / 8
+		if err := ValidateWavFormat(&depth); err != nil {
+			t.Errorf("Expected %d-bit WAV to be valid, got error: %v", bits, err)
+		}
+	}
+
+	invalidAlign := *validWav
+	invalidAlign.FmtChunk.BitsPerSample = 24
+	err = ValidateWavFormat(&invalidAlign)
+	if err != ErrInvalidBlockAlign {
+		t.Errorf("Expected ErrInvalidBlockAlign, got %v", err)
+	}
 }
 
 func TestRoundTripConversion(t *testing.T) {
@@ -221,7 +246,7 @@ func TestWriteWavFile(t *testing.T) {
 		t.Fatalf("Failed to write WAV file: %v", err)
 	}
 
-	readWav, err := ReadWavFile(outFilePath)
+	readWav, err := ReadWavFile(outFilePath, StrictPolicy)
 	if err != nil {
 		t.Fatalf("Failed to read written WAV file: %v", err)
 	}
@@ -238,3 +263,330 @@ func TestWriteWavFile(t *testing.T) {
 		}
 	}
 }
+
+func TestConvertBitDepths(t *testing.T) {
+	tests := []struct {
+		bits     uint16
+		data     []byte
+		expected []float64
+	}{
+		{8, []byte{0x80, 0xC0, 0x40, 0x00, 0xFF}, []float64{0, 0.5, -0.5, -1, 127.0 / 128}},
+		{24, []byte{0x00, 0x00, 0x40, 0x00, 0x00, 0xC0, 0xFF, 0xFF, 0xFF}, []float64{0.5, -0.5, -1.0 / (1 << 23)}},
+		{32, []byte{0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0x00, 0x80}, []float64{0.5, -1}},
+	}
+
+	for _, test := range tests {
+		format := DefaultFormat()
+		format.BitsPerSample = test.bits
+		format.BlockAlign = test.bits / 8
+
+		reader := NewWavReader()
+		reader.Format = format
+		samples := reader.ConvertToSamples(test.data)
+
+		if len(samples) != len(test.expected) {
+			t.Fatalf("%d-bit: expected %d samples, got %d", test.bits, len(test.expected), len(samples))
+		}
+		for i, expected := range test.expected {
+			if samples[i] != expected {
+				t.Errorf("%d-bit sample %d: expected %v, got %v", test.bits, i, expected, samples[i])
+			}
+		}
+
+		writer := NewWavWriter()
+		writer.Format = format
+		if data := writer.ConvertFromSamples(samples); string(data) != string(test.data) {
+			t.Errorf("%d-bit: expected bytes %v, got %v", test.bits, test.data, data)
+		}
+	}
+}
+
+func TestWriteWavFileBitDepths(t *testing.T) {
+	samples := []float64{0, 0.25, -0.75, 0.5, -1, 0.999}
+
+	for _, bits := range []uint16{8, 16, 24, 32} {
+		wavFile := NewWavFile(append([]float64{}, samples...), 48000, 2)
+		wavFile.FmtChunk.BitsPerSample = bits
+		wavFile.FmtChunk.BlockAlign = 2 * bits / 8
+		wavFile.FmtChunk.ByteRate = 48000 * uint32(wavFile.FmtChunk.BlockAlign)
+
+		outFilePath := filepath.Join(t.TempDir(), "depth.wav")
+		if err := WriteWavFile(outFilePath, wavFile); err != nil {
+			t.Fatalf("%d-bit: failed to write WAV file: %v", bits, err)
+		}
+
+		readWav, err := ReadWavFile(outFilePath, StrictPolicy)
+		if err != nil {
+			t.Fatalf("%d-bit: failed to read WAV file: %v", bits, err)
+		}
+
+		if readWav.FmtChunk.BitsPerSample != bits || len(readWav.DataChunk.Data) != len(samples)*int(bits)/8 {
+			t.Fatalf("%d-bit: unexpected format %+v with %d data bytes", bits, readWav.FmtChunk, len(readWav.DataChunk.Data))
+		}
+
+		epsilon := 1 / FullScale(bits)
+		for i, expected := range samples {
+			if diff := readWav.Samples[i] - expected; diff > epsilon || diff < -epsilon {
+				t.Errorf("%d-bit sample %d: expected %.6f, got %.6f", bits, i, expected, readWav.Samples[i])
+			}
+		}
+	}
+}
+
+func TestFloatWavRoundTrip(t *testing.T) {
+	samples := []float64{0, 0.5, -0.25, 1.5, -2.75, 1e-7}
+
+	for _, bits := range []uint16{32, 64} {
+		wavFile := NewWavFile(append([]float64{}, samples...), 48000, 1)
+		wavFile.FmtChunk.AudioFormat = FormatIEEEFloat
+		wavFile.FmtChunk.BitsPerSample = bits
+		wavFile.FmtChunk.BlockAlign = bits / 8
+		wavFile.FmtChunk.ByteRate = 48000 * uint32(bits/8)
+
+		outFilePath := filepath.Join(t.TempDir(), "float.wav")
+		if err := WriteWavFile(outFilePath, wavFile); err != nil {
+			t.Fatalf("%d-bit float: failed to write WAV file: %v", bits, err)
+		}
+
+		readWav, err := ReadWavFile(outFilePath, StrictPolicy)
+		if err != nil {
+			t.Fatalf("%d-bit float: failed to read WAV file: %v", bits, err)
+		}
+
+		if readWav.FmtChunk.AudioFormat != FormatIEEEFloat || readWav.FmtChunk.BitsPerSample != bits {
+			t.Fatalf("%d-bit float: unexpected format %+v", bits, readWav.FmtChunk)
+		}
+
+		for i, expected := range samples {
+			if bits == 32 {
+				expected = float64(float32(expected))
+			}
+			if readWav.Samples[i] != expected {
+				t.Errorf("%d-bit float sample %d: expected %v, got %v", bits, i, expected, readWav.Samples[i])
+			}
+		}
+	}
+}
+
+func TestValidateFloatFormat(t *testing.T) {
+	floatWav := &WavFile{
+		FmtChunk: FmtSubChunk{
+			AudioFormat:   FormatIEEEFloat,
+			NumChannels:   2,
+			SampleRate:    48000,
+			BlockAlign:    8,
+			BitsPerSample: 32,
+		},
+	}
+
+	if err := ValidateWavFormat(floatWav); err != nil {
+		t.Errorf("Expected 32-bit float WAV to be valid, got error: %v", err)
+	}
+
+	floatWav.FmtChunk.BitsPerSample = 16
+	floatWav.FmtChunk.BlockAlign = 4
+	if err := ValidateWavFormat(floatWav); err != ErrInvalidBitsPerSample {
+		t.Errorf("Expected ErrInvalidBitsPerSample for 16-bit float, got %v", err)
+	}
+
+	if scale := SampleScale(FmtSubChunk{AudioFormat: FormatIEEEFloat, BitsPerSample: 32}); scale != 1<<23 {
+		t.Errorf("Expected float32 sample scale 2^23, got %v", scale)
+	}
+}
+
+func TestChannels(t *testing.T) {
+	wavFile := NewWavFile([]float64{1, -1, 2, -2, 3, -3}, 48000, 2)
+
+	if wavFile.NumFrames() != 3 {
+		t.Fatalf("Expected 3 frames, got %d", wavFile.NumFrames())
+	}
+	if frame := wavFile.Frame(1); frame[0] != 2 || frame[1] != -2 {
+		t.Errorf("Expected frame [2 -2], got %v", frame)
+	}
+
+	channels := wavFile.Channels()
+	if len(channels) != 2 || channels[0][2] != 3 || channels[1][0] != -1 {
+		t.Fatalf("Unexpected channels %v", channels)
+	}
+	if right := wavFile.Channel(1); right[1] != -2 {
+		t.Errorf("Expected right channel sample -2, got %v", right[1])
+	}
+
+	if mix := Downmix(channels); mix[0] != 0 || mix[2] != 0 {
+		t.Errorf("Expected silent downmix, got %v", mix)
+	}
+
+	mid, side := MidSide(channels[0], channels[1])
+	if mid[1] != 0 || side[1] != 2 {
+		t.Errorf("Expected mid 0 and side 2, got %v and %v", mid[1], side[1])
+	}
+
+	wavFile.SetChannels([][]float64{channels[0], channels[1], mid})
+	if wavFile.FmtChunk.NumChannels != 3 || wavFile.FmtChunk.BlockAlign != 6 || len(wavFile.Samples) != 9 {
+		t.Errorf("Unexpected format after SetChannels: %+v with %d samples", wavFile.FmtChunk, len(wavFile.Samples))
+	}
+	if wavFile.Samples[3] != 2 || wavFile.Samples[5] != 0 {
+		t.Errorf("Unexpected interleaving %v", wavFile.Samples)
+	}
+}
+
+func TestValidationPolicy(t *testing.T) {
+	wavFile := &WavFile{
+		FmtChunk: FmtSubChunk{
+			AudioFormat:   FormatPCM,
+			NumChannels:   2,
+			SampleRate:    44100,
+			BlockAlign:    4,
+			BitsPerSample: 16,
+		},
+	}
+
+	if err := StrictPolicy.Validate(wavFile); err != ErrInvalidSampleRate {
+		t.Errorf("Expected strict policy to reject 44100 Hz, got %v", err)
+	}
+
+	for _, rate := range []uint32{8000, 44100, 96000, 384000} {
+		wavFile.FmtChunk.SampleRate = rate
+		if err := PermissivePolicy.Validate(wavFile); err != nil {
+			t.Errorf("Expected permissive policy to accept %d Hz, got %v", rate, err)
+		}
+	}
+
+	for _, rate := range []uint32{4000, 768000} {
+		wavFile.FmtChunk.SampleRate = rate
+		if err := PermissivePolicy.Validate(wavFile); err != ErrInvalidSampleRate {
+			t.Errorf("Expected permissive policy to reject %d Hz, got %v", rate, err)
+		}
+	}
+
+	wavFile.FmtChunk.SampleRate = 44100
+	if err := (ValidationPolicy{}).Validate(wavFile); err != ErrInvalidSampleRate {
+		t.Errorf("Expected the zero policy to behave as strict, got %v", err)
+	}
+
+	if policy, err := ParsePolicy("permissive"); err != nil || policy != PermissivePolicy {
+		t.Errorf("Expected the permissive policy, got %+v (%v)", policy, err)
+	}
+	if _, err := ParsePolicy("lenient"); !errors.Is(err, ErrUnknownPolicy) {
+		t.Errorf("Expected ErrUnknownPolicy, got %v", err)
+	}
+}
+
+func TestReadWavFilePolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cd.wav")
+	if err := WriteWavFile(path, NewWavFile(make([]float64, 441), 44100, 1)); err != nil {
+		t.Fatalf("Failed to write WAV file: %v", err)
+	}
+
+	for _, policy := range []ValidationPolicy{{}, StrictPolicy} {
+		if _, err := ReadWavFile(path, policy); !errors.Is(err, ErrInvalidSampleRate) {
+			t.Errorf("Expected %q policy to reject 44100 Hz, got %v", policy.Name, err)
+		}
+	}
+
+	wavFile, err := ReadWavFile(path, PermissivePolicy)
+	if err != nil {
+		t.Fatalf("Expected permissive policy to read 44100 Hz, got %v", err)
+	}
+	if wavFile.FmtChunk.SampleRate != 44100 || len(wavFile.Samples) != 441 {
+		t.Errorf("Unexpected file %+v with %d samples", wavFile.FmtChunk, len(wavFile.Samples))
+	}
+}
+
+func TestExtensibleRoundTrip(t *testing.T) {
+	tests := []struct {
+		subFormat GUID
+		bits      uint16
+		validBits uint16
+	}{
+		{SubFormatPCM, 24, 20},
+		{SubFormatPCM, 32, 24},
+		{SubFormatIEEEFloat, 32, 32},
+	}
+
+	for _, test := range tests {
+		samples := make([]float64, 6*4)
+		for i := range samples {
+			samples[i] = float64(i%7-3) / 8
+		}
+
+		wavFile := NewWavFile(append([]float64{}, samples...), 48000, 6)
+		wavFile.FmtChunk.AudioFormat = FormatExtensible
+		wavFile.FmtChunk.BitsPerSample = test.bits
+		wavFile.FmtChunk.BlockAlign = 6 * test.bits / 8
+		wavFile.FmtChunk.ByteRate = 48000 * uint32(wavFile.FmtChunk.BlockAlign)
+		wavFile.FmtChunk.ValidBitsPerSample = test.validBits
+		wavFile.FmtChunk.ChannelMask = 0x3F
+		wavFile.FmtChunk.SubFormat = test.subFormat
+
+		outFilePath := filepath.Join(t.TempDir(), "extensible.wav")
+		if err := WriteWavFile(outFilePath, wavFile); err != nil {
+			t.Fatalf("Failed to write extensible WAV file: %v", err)
+		}
+
+		readWav, err := ReadWavFile(outFilePath, StrictPolicy)
+		if err != nil {
+			t.Fatalf("Failed to read extensible WAV file: %v", err)
+		}
+
+		format := readWav.FmtChunk
+		if format.SubChunkSize != 40 || format.ExtensionSize != 22 || format.ValidBitsPerSample != test.validBits ||
+			format.ChannelMask != 0x3F || format.SubFormat != test.subFormat {
+			t.Errorf("Unexpected extensible format %+v", format)
+		}
+		if readWav.Header.ChunkSize != 4+48+8+uint32(len(readWav.DataChunk.Data)) {
+			t.Errorf("Unexpected RIFF chunk size %d", readWav.Header.ChunkSize)
+		}
+
+		for i, expected := range samples {
+			if readWav.Samples[i] != expected {
+				t.Errorf("Sample %d: expected %v, got %v", i, expected, readWav.Samples[i])
+			}
+		}
+
+		if scale := SampleScale(format); test.subFormat == SubFormatPCM && scale != FullScale(test.validBits) {
+			t.Errorf("Expected sample scale for %d valid bits, got %v", test.validBits, scale)
+		}
+	}
+}
+
+func TestReadExtensibleHeader(t *testing.T) {
+	header := []byte{
+		'R', 'I', 'F', 'F', 0, 0, 0, 0, 'W', 'A', 'V', 'E',
+		'f', 'm', 't', ' ', 40, 0, 0, 0,
+		0xFE, 0xFF,
+		2, 0,
+		0x80, 0xBB, 0, 0,
+		0x00, 0x65, 0x04, 0,
+		6, 0,
+		24, 0,
+		22, 0,
+		24, 0,
+		3, 0, 0, 0,
+	}
+	header = append(header, SubFormatPCM[:]...)
+	header = append(header, 'd', 'a', 't', 'a', 6, 0, 0, 0, 0x00, 0x00, 0x40, 0x00, 0x00, 0xC0)
+
+	path := filepath.Join(t.TempDir(), "extensible.wav")
+	if err := os.WriteFile(path, header, 0644); err != nil {
+		t.Fatalf("Failed to create test WAV file: %v", err)
+	}
+
+	wavFile, err := ReadWavFile(path, StrictPolicy)
+	if err != nil {
+		t.Fatalf("Failed to read extensible WAV file: %v", err)
+	}
+
+	if wavFile.FmtChunk.SampleFormat() != FormatPCM || wavFile.FmtChunk.ChannelMask != 3 {
+		t.Errorf("Unexpected format %+v", wavFile.FmtChunk)
+	}
+	if len(wavFile.Samples) != 2 || wavFile.Samples[0] != 0.5 || wavFile.Samples[1] != -0.5 {
+		t.Errorf("Expected samples [0.5 -0.5], got %v", wavFile.Samples)
+	}
+
+	unknown := *wavFile
+	unknown.FmtChunk.SubFormat = GUID{0x02}
+	if err := ValidateWavFormat(&unknown); err != ErrUnsupportedAudioFormat {
+		t.Errorf("Expected ErrUnsupportedAudioFormat for an unknown sub-format, got %v", err)
+	}
+}
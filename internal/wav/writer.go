@@ -3,12 +3,14 @@ package wav
 import (
 	"encoding/binary"
 	"fmt"
+	"math"
 	"os"
 )
 
 func NewWavWriter() *WavWriter {
 	return &WavWriter{
 		File:       nil,
+		Format:     DefaultFormat(),
 		endianness: binary.LittleEndian,
 		current:    0,
 		headerSize: 0,
@@ -28,9 +30,9 @@ func (w *WavWriter) WriteHeader(file *os.File, header WavHeader) error {
 		return fmt.Errorf("binary.Write(file, w.endianness, header.ChunkID): %w", err)
 	}
 
-	err = binary.Write(file, w.endianness, uint32(w.headerSize))
+	err = binary.Write(file, w.endianness, header.ChunkSize)
 	if err != nil {
-		return fmt.Errorf("binary.Write(file, w.endianness, uint32(w.headerSize)): %w", err)
+		return fmt.Errorf("binary.Write(file, w.endianness, header.ChunkSize): %w", err)
 	}
 
 	err = binary.Write(file, w.endianness, header.Format)
@@ -54,9 +56,9 @@ func (w *WavWriter) WriteFmtChunk(file *os.File, fmtChunk FmtSubChunk) error {
 		return fmt.Errorf("binary.Write(file, w.endianness, FourCC{'f', 'm', 't', ' '}): %w", err)
 	}
 
-	err = binary.Write(file, w.endianness, uint32(16))
+	err = binary.Write(file, w.endianness, fmtChunk.Size())
 	if err != nil {
-		return fmt.Errorf("binary.Write(file, w.endianness, uint32(16)): %w", err)
+		return fmt.Errorf("binary.Write(file, w.endianness, fmtChunk.Size()): %w", err)
 	}
 
 	err = binary.Write(file, w.endianness, fmtChunk.AudioFormat)
@@ -89,7 +91,36 @@ func (w *WavWriter) WriteFmtChunk(file *os.File, fmtChunk FmtSubChunk) error {
 		return fmt.Errorf("binary.Write(file, w.endianness, fmtChunk.BitsPerSample): %w", err)
 	}
 
-	w.current += 24
+	if fmtChunk.Size() >= fmtExtendedSize {
+		var size uint16
+		if fmtChunk.AudioFormat == FormatExtensible {
+			size = extensionSize
+		}
+
+		err = binary.Write(file, w.endianness, size)
+		if err != nil {
+			return fmt.Errorf("binary.Write(file, w.endianness, size): %w", err)
+		}
+	}
+
+	if fmtChunk.AudioFormat == FormatExtensible {
+		err = binary.Write(file, w.endianness, fmtChunk.ValidBitsPerSample)
+		if err != nil {
+			return fmt.Errorf("binary.Write(file, w.endianness, fmtChunk.ValidBitsPerSample): %w", err)
+		}
+
+		err = binary.Write(file, w.endianness, fmtChunk.ChannelMask)
+		if err != nil {
+			return fmt.Errorf("binary.Write(file, w.endianness, fmtChunk.ChannelMask): %w", err)
+		}
+
+		err = binary.Write(file, w.endianness, fmtChunk.SubFormat)
+		if err != nil {
+			return fmt.Errorf("binary.Write(file, w.endianness, fmtChunk.SubFormat): %w", err)
+		}
+	}
+
+	w.current += 8 + int64(fmtChunk.Size())
 
 	return nil
 }
@@ -132,15 +163,46 @@ func (w *WavWriter) WriteDataChunk(file *os.File, dataChunk DataSubChunk) error
 }
 
 func (w *WavWriter) ConvertFromSamples(samples []float64) []byte {
-	var data = make([]byte, len(samples)*2)
+	width := SampleWidth(w.Format)
+	var data = make([]byte, len(samples)*width)
+	fullScale := FullScale(w.Format.BitsPerSample)
+
+	if w.Format.SampleFormat() == FormatIEEEFloat {
+		for i, sample := range samples {
+			encodeFloat(data[i*width:(i+1)*width], sample)
+		}
+		return data
+	}
 
 	for i, sample := range samples {
-		binary.LittleEndian.PutUint16(data[i*2:], uint16(sample*32767))
+		value := math.Round(sample * fullScale)
+		value = math.Max(-fullScale, math.Min(fullScale-1, value))
+		encodeSample(data[i*width:(i+1)*width], int64(value))
 	}
 
 	return data
 }
 
+func NewWavFile(samples []float64, sampleRate uint32, numChannels uint16) *WavFile {
+	format := DefaultFormat()
+	format.NumChannels = numChannels
+	format.SampleRate = sampleRate
+	format.BlockAlign = numChannels * 2
+	format.ByteRate = sampleRate * uint32(format.BlockAlign)
+
+	return &WavFile{
+		Header: WavHeader{
+			ChunkID: FourCC{'R', 'I', 'F', 'F'},
+			Format:  FourCC{'W', 'A', 'V', 'E'},
+		},
+		FmtChunk: format,
+		DataChunk: DataSubChunk{
+			SubChunkID: FourCC{'d', 'a', 't', 'a'},
+		},
+		Samples: samples,
+	}
+}
+
 func WriteWavFile(filePath string, wavFile *WavFile) error {
 	file, err := os.Create(filePath)
 	if err != nil {
@@ -150,12 +212,16 @@ func WriteWavFile(filePath string, wavFile *WavFile) error {
 
 	writer := NewWavWriter()
 	writer.File = file
+	writer.Format = wavFile.FmtChunk
 
 	if len(wavFile.Samples) > 0 {
 		wavFile.DataChunk.Data = writer.ConvertFromSamples(wavFile.Samples)
 		wavFile.DataChunk.SubChunkSize = uint32(len(wavFile.DataChunk.Data))
 	}
 
+	dataSize := uint32(len(wavFile.DataChunk.Data))
+	wavFile.Header.ChunkSize = 4 + (8 + wavFile.FmtChunk.Size()) + (8 + dataSize + dataSize%2)
+
 	if err := writer.WriteHeader(file, wavFile.Header); err != nil {
 		return fmt.Errorf("writer.WriteHeader(): %w", err)
 	}